@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Illuminant names a reference white point
+type Illuminant string
+
+const (
+	IlluminantD50 Illuminant = "D50" // CSS lab()/lch() reference white, print
+	IlluminantD55 Illuminant = "D55" // mid-morning daylight, photography
+	IlluminantD65 Illuminant = "D65" // sRGB / Display P3 / Rec.2020 reference white
+	IlluminantA   Illuminant = "A"   // incandescent tungsten
+	IlluminantE   Illuminant = "E"   // equal energy
+)
+
+// illuminantWhites holds the XYZ of each white point, normalized to Y = 1
+// D50 and D65 use the chromaticities from CSS Color Module Level 4
+var illuminantWhites = map[Illuminant][3]float64{
+	IlluminantD50: whiteFromChromaticity(0.3457, 0.3585),
+	IlluminantD55: whiteFromChromaticity(0.33242, 0.34743),
+	IlluminantD65: whiteFromChromaticity(0.3127, 0.3290),
+	IlluminantA:   whiteFromChromaticity(0.44757, 0.40745),
+	IlluminantE:   {1.0, 1.0, 1.0},
+}
+
+// ChromaticAdaptation names a chromatic adaptation transform (CAT)
+type ChromaticAdaptation string
+
+const (
+	AdaptationBradford ChromaticAdaptation = "bradford"
+	AdaptationCAT02    ChromaticAdaptation = "cat02"
+	AdaptationCAT16    ChromaticAdaptation = "cat16"
+	AdaptationVonKries ChromaticAdaptation = "von-kries"
+)
+
+// coneResponseMatrices convert XYZ to the cone-like response space of each CAT
+var coneResponseMatrices = map[ChromaticAdaptation][3][3]float64{
+	AdaptationBradford: {
+		{0.8951, 0.2664, -0.1614},
+		{-0.7502, 1.7135, 0.0367},
+		{0.0389, -0.0685, 1.0296},
+	},
+	AdaptationCAT02: {
+		{0.7328, 0.4296, -0.1624},
+		{-0.7036, 1.6975, 0.0061},
+		{0.0030, 0.0136, 0.9834},
+	},
+	AdaptationCAT16: {
+		{0.401288, 0.650173, -0.051461},
+		{-0.250268, 1.204414, 0.045854},
+		{-0.002079, 0.048952, 0.953127},
+	},
+	// Hunt-Pointer-Estevez cone fundamentals
+	AdaptationVonKries: {
+		{0.40024, 0.70760, -0.08081},
+		{-0.22630, 1.16532, 0.04570},
+		{0.0, 0.0, 0.91822},
+	},
+}
+
+// GetIlluminants returns the supported white points
+func GetIlluminants() []string {
+	return []string{
+		string(IlluminantD50), string(IlluminantD55), string(IlluminantD65),
+		string(IlluminantA), string(IlluminantE),
+	}
+}
+
+// GetChromaticAdaptations returns the supported chromatic adaptation transforms
+func GetChromaticAdaptations() []string {
+	return []string{
+		string(AdaptationBradford), string(AdaptationCAT02),
+		string(AdaptationCAT16), string(AdaptationVonKries),
+	}
+}
+
+// ParseIlluminant validates a white point name (case-insensitive); empty selects D50
+func ParseIlluminant(name string) (Illuminant, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return IlluminantD50, nil
+	}
+	if _, ok := illuminantWhites[Illuminant(name)]; !ok {
+		return "", fmt.Errorf("invalid white point: %s (supported: %s)", name, strings.Join(GetIlluminants(), ", "))
+	}
+	return Illuminant(name), nil
+}
+
+// ParseChromaticAdaptation validates a CAT name (case-insensitive); empty selects Bradford
+func ParseChromaticAdaptation(name string) (ChromaticAdaptation, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return AdaptationBradford, nil
+	}
+	if _, ok := coneResponseMatrices[ChromaticAdaptation(name)]; !ok {
+		return "", fmt.Errorf("invalid chromatic adaptation: %s (supported: %s)", name, strings.Join(GetChromaticAdaptations(), ", "))
+	}
+	return ChromaticAdaptation(name), nil
+}
+
+// adaptXYZ converts XYZ seen under one white point to the corresponding color
+// under another, using a von Kries-style scaling in the CAT's cone space
+func adaptXYZ(x, y, z float64, from, to Illuminant, method ChromaticAdaptation) (float64, float64, float64) {
+	if from == to {
+		return x, y, z
+	}
+	return mulMatrix3(adaptationMatrix(from, to, method), x, y, z)
+}
+
+// adaptationMatrix builds M⁻¹ · diag(ρ_to / ρ_from) · M for a CAT matrix M
+func adaptationMatrix(from, to Illuminant, method ChromaticAdaptation) [3][3]float64 {
+	m := coneResponseMatrices[method]
+	src := illuminantWhites[from]
+	dst := illuminantWhites[to]
+
+	s1, s2, s3 := mulMatrix3(m, src[0], src[1], src[2])
+	d1, d2, d3 := mulMatrix3(m, dst[0], dst[1], dst[2])
+	scale := [3][3]float64{
+		{d1 / s1, 0, 0},
+		{0, d2 / s2, 0},
+		{0, 0, d3 / s3},
+	}
+
+	return mulMatrices3(invertMatrix3(m), mulMatrices3(scale, m))
+}
+
+// whiteFromChromaticity converts xy chromaticity coordinates to XYZ with Y = 1
+func whiteFromChromaticity(x, y float64) [3]float64 {
+	return [3]float64{x / y, 1.0, (1 - x - y) / y}
+}
+
+// mulMatrices3 multiplies two 3x3 matrices
+func mulMatrices3(a, b [3][3]float64) [3][3]float64 {
+	var out [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			out[i][j] = a[i][0]*b[0][j] + a[i][1]*b[1][j] + a[i][2]*b[2][j]
+		}
+	}
+	return out
+}
+
+// invertMatrix3 inverts a 3x3 matrix using its adjugate
+func invertMatrix3(m [3][3]float64) [3][3]float64 {
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+
+	return [3][3]float64{
+		{
+			(m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det,
+			(m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det,
+			(m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det,
+		},
+		{
+			(m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det,
+			(m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det,
+			(m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det,
+		},
+		{
+			(m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det,
+			(m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det,
+			(m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det,
+		},
+	}
+}
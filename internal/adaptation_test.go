@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseIlluminant(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  Illuminant
+		expectErr bool
+	}{
+		{"", IlluminantD50, false},
+		{"d65", IlluminantD65, false},
+		{"D55", IlluminantD55, false},
+		{"a", IlluminantA, false},
+		{"F2", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			w, err := ParseIlluminant(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, w)
+			}
+		})
+	}
+}
+
+func TestParseChromaticAdaptation(t *testing.T) {
+	for _, name := range GetChromaticAdaptations() {
+		if _, err := ParseChromaticAdaptation(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if m, _ := ParseChromaticAdaptation(""); m != AdaptationBradford {
+		t.Errorf("expected bradford default, got %s", m)
+	}
+	if _, err := ParseChromaticAdaptation("xyz-scaling"); err == nil {
+		t.Error("expected error for unknown transform")
+	}
+}
+
+// TestAdaptXYZMapsWhites checks that every transform maps the source white onto the target white
+func TestAdaptXYZMapsWhites(t *testing.T) {
+	for _, method := range GetChromaticAdaptations() {
+		for _, from := range GetIlluminants() {
+			for _, to := range GetIlluminants() {
+				src := illuminantWhites[Illuminant(from)]
+				dst := illuminantWhites[Illuminant(to)]
+				x, y, z := adaptXYZ(src[0], src[1], src[2], Illuminant(from), Illuminant(to), ChromaticAdaptation(method))
+				if math.Abs(x-dst[0]) > 1e-9 || math.Abs(y-dst[1]) > 1e-9 || math.Abs(z-dst[2]) > 1e-9 {
+					t.Errorf("%s %s->%s: got (%f, %f, %f), want %v", method, from, to, x, y, z, dst)
+				}
+			}
+		}
+	}
+}
+
+// TestBradfordMatchesCSS checks the derived D65 -> D50 matrix against CSS Color 4
+func TestBradfordMatchesCSS(t *testing.T) {
+	expected := [3][3]float64{
+		{1.0479297925449969, 0.022946870601609652, -0.05019226628920524},
+		{0.02962780877005599, 0.9904344267538799, -0.017073799063418826},
+		{-0.009243040646204504, 0.015055191490298152, 0.7518742814281371},
+	}
+	m := adaptationMatrix(IlluminantD65, IlluminantD50, AdaptationBradford)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(m[i][j]-expected[i][j]) > 1e-9 {
+				t.Errorf("m[%d][%d] = %.12f, want %.12f", i, j, m[i][j], expected[i][j])
+			}
+		}
+	}
+}
+
+func TestLABWhitePoint(t *testing.T) {
+	tests := []struct {
+		input    string
+		format   ColorFormat
+		opts     ConvertOptions
+		expected string
+	}{
+		{"#FF0000", FormatLAB, ConvertOptions{}, "lab(54.29 80.80 69.89)"},
+		{"#FF0000", FormatLAB, ConvertOptions{WhitePoint: IlluminantD65}, "lab(53.24 80.09 67.20)"},
+		{"#FF0000", FormatLCH, ConvertOptions{}, "lch(54.29 106.84 40.86)"},
+		{"#FFFFFF", FormatLAB, ConvertOptions{WhitePoint: IlluminantA, Adaptation: AdaptationCAT16}, "lab(100.00 0.00 0.00)"},
+		{"#FFFFFF", FormatLAB, ConvertOptions{WhitePoint: IlluminantD55, Adaptation: AdaptationVonKries}, "lab(100.00 0.00 0.00)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input+"/"+string(tt.opts.WhitePoint)+"/"+string(tt.opts.Adaptation), func(t *testing.T) {
+			tt.opts.PreserveAlpha = true
+			result, err := ConvertWithOptions(tt.input, string(tt.format), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := ConvertWithOptions("#FF0000", "lab", ConvertOptions{WhitePoint: "D75"}); err == nil {
+		t.Error("expected error for unknown white point")
+	}
+}
+
+// TestLABInputIsD50 checks that lab() input is read against the CSS D50 white
+func TestLABInputIsD50(t *testing.T) {
+	result, err := Convert("lab(54.29 80.80 69.89)", "hex", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "#FF0000" {
+		t.Errorf("expected #FF0000, got %s", result)
+	}
+}
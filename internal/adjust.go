@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AdjustChannel names a channel an adjust operation changes
+type AdjustChannel string
+
+const (
+	AdjustLightness  AdjustChannel = "lightness"
+	AdjustChroma     AdjustChannel = "chroma"     // saturation in HSL
+	AdjustSaturation AdjustChannel = "saturation" // alias of chroma
+	AdjustHue        AdjustChannel = "hue"
+	AdjustAlpha      AdjustChannel = "alpha"
+	AdjustWhiteness  AdjustChannel = "whiteness"
+	AdjustBlackness  AdjustChannel = "blackness"
+)
+
+// AdjustMode selects whether an operation adds to a channel or sets it
+type AdjustMode string
+
+const (
+	AdjustRelative AdjustMode = "relative" // add the value to the channel (negative values subtract)
+	AdjustAbsolute AdjustMode = "absolute" // set the channel to the value
+)
+
+// AdjustOperation is one channel change applied by AdjustColor
+// Values use the units of the model: OKLCH lightness 0-1 and chroma 0-0.4,
+// LCH lightness 0-100 and chroma 0-150, HSL/HWB percentages 0-100, hue in
+// degrees and alpha 0-1
+type AdjustOperation struct {
+	Channel AdjustChannel
+	Mode    AdjustMode
+	Value   float64
+	Model   ColorFormat // oklch, lch, hsl or hwb (default: oklch)
+}
+
+// adjustModels maps each color model to the coordinate index of its channels
+var adjustModels = map[ColorFormat]map[AdjustChannel]int{
+	FormatOKLCH: {AdjustLightness: 0, AdjustChroma: 1, AdjustHue: 2},
+	FormatLCH:   {AdjustLightness: 0, AdjustChroma: 1, AdjustHue: 2},
+	FormatHSL:   {AdjustHue: 0, AdjustChroma: 1, AdjustLightness: 2},
+	FormatHWB:   {AdjustHue: 0, AdjustWhiteness: 1, AdjustBlackness: 2},
+}
+
+// adjustRanges holds the valid range of each non-hue coordinate of a model
+var adjustRanges = map[ColorFormat][3][2]float64{
+	FormatOKLCH: {{0, OKLCH_L_Max}, {0, math.Inf(1)}, {}},
+	FormatLCH:   {{0, LAB_L_Max}, {0, math.Inf(1)}, {}},
+	FormatHSL:   {{}, {0, SaturationMax}, {0, LightnessMax}},
+	FormatHWB:   {{}, {0, LightnessMax}, {0, LightnessMax}},
+}
+
+// GetAdjustChannels returns the channels adjust operations can change
+func GetAdjustChannels() []string {
+	return []string{
+		string(AdjustLightness), string(AdjustChroma), string(AdjustSaturation), string(AdjustHue),
+		string(AdjustAlpha), string(AdjustWhiteness), string(AdjustBlackness),
+	}
+}
+
+// GetAdjustModes returns the supported adjust modes
+func GetAdjustModes() []string {
+	return []string{string(AdjustRelative), string(AdjustAbsolute)}
+}
+
+// GetAdjustModels returns the color models adjust operations run in
+func GetAdjustModels() []string {
+	return []string{string(FormatOKLCH), string(FormatHSL), string(FormatLCH), string(FormatHWB)}
+}
+
+// parseAdjustOperation validates an operation and fills in defaults
+func parseAdjustOperation(op AdjustOperation) (AdjustOperation, error) {
+	op.Channel = AdjustChannel(strings.ToLower(strings.TrimSpace(string(op.Channel))))
+	if op.Channel == AdjustSaturation {
+		op.Channel = AdjustChroma
+	}
+
+	switch m := AdjustMode(strings.ToLower(strings.TrimSpace(string(op.Mode)))); m {
+	case "":
+		op.Mode = AdjustRelative
+	case AdjustRelative, AdjustAbsolute:
+		op.Mode = m
+	default:
+		return op, fmt.Errorf("invalid adjust mode: %s (supported: %s)", op.Mode, strings.Join(GetAdjustModes(), ", "))
+	}
+
+	op.Model = ColorFormat(strings.ToLower(strings.TrimSpace(string(op.Model))))
+	if op.Model == "" {
+		op.Model = FormatOKLCH
+		if op.Channel == AdjustWhiteness || op.Channel == AdjustBlackness {
+			op.Model = FormatHWB
+		}
+	}
+	channels, ok := adjustModels[op.Model]
+	if !ok {
+		return op, fmt.Errorf("invalid adjust model: %s (supported: %s)", op.Model, strings.Join(GetAdjustModels(), ", "))
+	}
+
+	if op.Channel == AdjustAlpha {
+		return op, nil
+	}
+	if _, ok := channels[op.Channel]; !ok {
+		valid := false
+		for _, c := range GetAdjustChannels() {
+			valid = valid || AdjustChannel(c) == op.Channel
+		}
+		if !valid {
+			return op, fmt.Errorf("invalid adjust channel: %s (supported: %s)", op.Channel, strings.Join(GetAdjustChannels(), ", "))
+		}
+		return op, fmt.Errorf("channel %s is not available in %s", op.Channel, op.Model)
+	}
+	return op, nil
+}
+
+// apply runs the operation on a color
+func (op AdjustOperation) apply(lc LinearColor) LinearColor {
+	if op.Channel == AdjustAlpha {
+		lc.A = clamp(op.update(lc.A), AlphaMin, AlphaMax)
+		return lc
+	}
+
+	space := interpolationSpaces[op.Model]
+	coords := space.toCoords(lc)
+	i := adjustModels[op.Model][op.Channel]
+	if i == space.hue {
+		coords[i] = normalizeHue(op.update(coords[i]))
+	} else {
+		r := adjustRanges[op.Model][i]
+		coords[i] = clamp(op.update(coords[i]), r[0], r[1])
+	}
+
+	adjusted := space.fromCoords(coords)
+	adjusted.A = lc.A
+	return adjusted
+}
+
+// update returns the new value of a channel
+func (op AdjustOperation) update(v float64) float64 {
+	if op.Mode == AdjustAbsolute {
+		return op.Value
+	}
+	return v + op.Value
+}
+
+// String describes the operation, e.g. "oklch lightness +0.1"
+func (op AdjustOperation) String() string {
+	value := strconv.FormatFloat(op.Value, 'f', -1, 64)
+	if op.Mode == AdjustAbsolute {
+		value = "= " + value
+	} else if op.Value >= 0 {
+		value = "+" + value
+	}
+	switch {
+	case op.Channel == AdjustAlpha:
+		return fmt.Sprintf("alpha %s", value)
+	case op.Channel == AdjustChroma && op.Model == FormatHSL:
+		return fmt.Sprintf("%s %s %s", op.Model, AdjustSaturation, value)
+	default:
+		return fmt.Sprintf("%s %s %s", op.Model, op.Channel, value)
+	}
+}
+
+// AdjustStep is the color after one adjust operation
+type AdjustStep struct {
+	Operation AdjustOperation
+	Color     string
+}
+
+// AdjustResult holds the steps and final color of AdjustColor
+type AdjustResult struct {
+	Original ColorData
+	Format   string
+	Steps    []AdjustStep
+	Result   string
+}
+
+// AdjustColor applies a list of channel operations to a color, in order, and
+// returns the result in the target format
+func AdjustColor(color string, ops []AdjustOperation, targetFormat string, opts ConvertOptions) (*AdjustResult, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("at least one operation is required")
+	}
+
+	data, err := DetectFormat(color)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+
+	result := &AdjustResult{Original: data, Format: targetFormat}
+	lc := data.Linear
+	for i, op := range ops {
+		op, err = parseAdjustOperation(op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i+1, err)
+		}
+		lc = op.apply(lc)
+
+		formatted, err := FormatColor(ColorData{Color: lc.SRGB(), Linear: lc}, targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Steps = append(result.Steps, AdjustStep{Operation: op, Color: formatted})
+	}
+	result.Result = result.Steps[len(result.Steps)-1].Color
+
+	return result, nil
+}
+
+// FormatAdjustment formats the steps of an adjustment as text
+func FormatAdjustment(result *AdjustResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Adjust: %s (%s)\n\n", result.Original.Original, result.Original.Format))
+	for i, step := range result.Steps {
+		builder.WriteString(fmt.Sprintf("  %d. %s → %s\n", i+1, step.Operation, step.Color))
+	}
+	builder.WriteString(fmt.Sprintf("\nResult: %s (format: %s)", result.Result, result.Format))
+	return builder.String()
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdjustColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    string
+		ops      []AdjustOperation
+		format   string
+		expected string
+	}{
+		{"oklch lighten", "#3b82f6", []AdjustOperation{{Channel: AdjustLightness, Value: 0.1}}, "hex", "#5AA2FF"},
+		{"hsl darken", "hsl(0, 100%, 50%)", []AdjustOperation{{Channel: AdjustLightness, Value: -25, Model: FormatHSL}}, "hsl", "hsl(0, 100%, 25%)"},
+		{"hsl desaturate", "hsl(0, 100%, 50%)", []AdjustOperation{{Channel: AdjustSaturation, Value: -100, Model: FormatHSL}}, "hex", "#808080"},
+		{"hsl rotate", "red", []AdjustOperation{{Channel: AdjustHue, Value: 120, Model: FormatHSL}}, "hex", "#00FF00"},
+		{"hsl rotate wraps", "red", []AdjustOperation{{Channel: AdjustHue, Value: -120, Model: FormatHSL}}, "hex", "#0000FF"},
+		{"set hue", "#3b82f6", []AdjustOperation{{Channel: AdjustHue, Mode: AdjustAbsolute, Value: 200, Model: FormatHSL}}, "hex", "#3BB8F6"},
+		{"hwb defaults", "red", []AdjustOperation{{Channel: AdjustWhiteness, Value: 20}}, "hwb", "hwb(0 20% 0%)"},
+		{"hwb whiteness past 100% is gray", "hwb(0 0% 50%)", []AdjustOperation{{Channel: AdjustWhiteness, Mode: AdjustAbsolute, Value: 80}}, "hex", "#9D9D9D"},
+		{"alpha", "red", []AdjustOperation{{Channel: AdjustAlpha, Mode: AdjustAbsolute, Value: 0.5}}, "hex", "#FF000080"},
+		{"clamps lightness", "red", []AdjustOperation{{Channel: AdjustLightness, Value: 200, Model: FormatHSL}}, "hex", "#FFFFFF"},
+		{"lch chroma", "#808080", []AdjustOperation{{Channel: AdjustChroma, Mode: AdjustAbsolute, Value: 0, Model: FormatLCH}}, "hex", "#808080"},
+		{"chain", "#3b82f6", []AdjustOperation{
+			{Channel: AdjustLightness, Value: 0.1},
+			{Channel: AdjustHue, Mode: AdjustAbsolute, Value: 200, Model: FormatHSL},
+			{Channel: AdjustSaturation, Value: -20, Model: FormatHSL},
+		}, "hex", "#6AC2EE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := AdjustColor(tt.color, tt.ops, tt.format, ConvertOptions{PreserveAlpha: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result.Result)
+			}
+			if len(result.Steps) != len(tt.ops) {
+				t.Errorf("expected %d steps, got %d", len(tt.ops), len(result.Steps))
+			}
+		})
+	}
+}
+
+func TestAdjustColorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []AdjustOperation
+		want string
+	}{
+		{"no operations", nil, "at least one operation"},
+		{"unknown channel", []AdjustOperation{{Channel: "temperature", Value: 1}}, "invalid adjust channel"},
+		{"channel not in model", []AdjustOperation{{Channel: AdjustWhiteness, Value: 10, Model: FormatOKLCH}}, "not available in oklch"},
+		{"unknown model", []AdjustOperation{{Channel: AdjustHue, Value: 10, Model: FormatCMYK}}, "invalid adjust model"},
+		{"unknown mode", []AdjustOperation{{Channel: AdjustHue, Mode: "multiply", Value: 10}}, "invalid adjust mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AdjustColor("red", tt.ops, "hex", ConvertOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
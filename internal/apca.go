@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContrastAlgorithm selects which contrast measure compare_colors reports
+type ContrastAlgorithm string
+
+const (
+	ContrastWCAG2 ContrastAlgorithm = "wcag2" // WCAG 2.x contrast ratio
+	ContrastAPCA  ContrastAlgorithm = "apca"  // APCA lightness contrast (WCAG 3 draft)
+	ContrastBoth  ContrastAlgorithm = "both"
+)
+
+// GetContrastAlgorithms returns the supported contrast algorithms
+func GetContrastAlgorithms() []string {
+	return []string{string(ContrastWCAG2), string(ContrastAPCA), string(ContrastBoth)}
+}
+
+// ParseContrastAlgorithm validates a contrast algorithm name; empty selects wcag2
+func ParseContrastAlgorithm(name string) (ContrastAlgorithm, error) {
+	switch a := ContrastAlgorithm(strings.ToLower(strings.TrimSpace(name))); a {
+	case "":
+		return ContrastWCAG2, nil
+	case ContrastWCAG2, ContrastAPCA, ContrastBoth:
+		return a, nil
+	default:
+		return "", fmt.Errorf("invalid contrast algorithm: %s (supported: %s)", name, strings.Join(GetContrastAlgorithms(), ", "))
+	}
+}
+
+// APCA-W3 0.1.9 constants (G-4g)
+const (
+	apcaMainTRC   = 2.4
+	apcaRCoef     = 0.2126729
+	apcaGCoef     = 0.7151522
+	apcaBCoef     = 0.0721750
+	apcaNormBG    = 0.56
+	apcaNormTXT   = 0.57
+	apcaRevTXT    = 0.62
+	apcaRevBG     = 0.65
+	apcaBlkThrs   = 0.022
+	apcaBlkClmp   = 1.414
+	apcaScale     = 1.14
+	apcaLoOffset  = 0.027
+	apcaDeltaYMin = 0.0005
+	apcaLoClip    = 0.1
+)
+
+// calculateAPCAContrast returns the APCA lightness contrast (Lc) of text on a
+// background. It is polarity-aware: dark text on a light background gives a
+// positive Lc (up to about 106), light text on a dark background a negative Lc
+// (down to about -108).
+func calculateAPCAContrast(text, background Color) float64 {
+	yText := apcaLuminance(text)
+	yBG := apcaLuminance(background)
+
+	if math.Abs(yBG-yText) < apcaDeltaYMin {
+		return 0
+	}
+
+	var out float64
+	if yBG > yText {
+		sapc := (math.Pow(yBG, apcaNormBG) - math.Pow(yText, apcaNormTXT)) * apcaScale
+		if sapc >= apcaLoClip {
+			out = sapc - apcaLoOffset
+		}
+	} else {
+		sapc := (math.Pow(yBG, apcaRevBG) - math.Pow(yText, apcaRevTXT)) * apcaScale
+		if sapc <= -apcaLoClip {
+			out = sapc + apcaLoOffset
+		}
+	}
+	return out * 100
+}
+
+// apcaLuminance returns the APCA screen luminance of a color, with the soft
+// clamp APCA applies near black
+func apcaLuminance(c Color) float64 {
+	y := apcaRCoef*math.Pow(c.R/RGBMax, apcaMainTRC) +
+		apcaGCoef*math.Pow(c.G/RGBMax, apcaMainTRC) +
+		apcaBCoef*math.Pow(c.B/RGBMax, apcaMainTRC)
+	if y < apcaBlkThrs {
+		y += math.Pow(apcaBlkThrs-y, apcaBlkClmp)
+	}
+	return y
+}
+
+// APCA font lookup sentinels
+const (
+	apcaFontProhibited = 999 // too little contrast for any use
+	apcaFontNonText    = 777 // enough for non-text elements only
+)
+
+// APCAFontWeights are the font weights of the APCA font lookup table
+var APCAFontWeights = []int{100, 200, 300, 400, 500, 600, 700, 800, 900}
+
+// apcaFontTable holds the minimum font size in px for each weight, per Lc
+// row (APCA-W3 fontLookupAPCA, G-4g)
+var apcaFontTable = []struct {
+	lc    float64
+	sizes [9]float64
+}{
+	{0, [9]float64{999, 999, 999, 999, 999, 999, 999, 999, 999}},
+	{10, [9]float64{999, 999, 999, 999, 999, 999, 999, 999, 999}},
+	{15, [9]float64{777, 777, 777, 777, 777, 777, 777, 777, 777}},
+	{20, [9]float64{777, 777, 777, 777, 777, 777, 777, 777, 777}},
+	{25, [9]float64{777, 777, 777, 120, 120, 108, 96, 96, 96}},
+	{30, [9]float64{777, 777, 120, 108, 108, 96, 72, 72, 72}},
+	{35, [9]float64{777, 120, 108, 96, 72, 60, 48, 48, 48}},
+	{40, [9]float64{120, 108, 96, 60, 48, 42, 32, 32, 32}},
+	{45, [9]float64{108, 96, 72, 42, 32, 28, 24, 24, 24}},
+	{50, [9]float64{96, 72, 60, 32, 28, 24, 21, 21, 21}},
+	{55, [9]float64{80, 60, 48, 28, 24, 21, 18, 18, 18}},
+	{60, [9]float64{72, 48, 42, 24, 21, 18, 16, 16, 18}},
+	{65, [9]float64{68, 46, 32, 21.75, 19, 17, 15, 16, 18}},
+	{70, [9]float64{64, 44, 28, 19.5, 18, 16, 14.5, 16, 18}},
+	{75, [9]float64{60, 42, 24, 18, 16, 15, 14, 16, 18}},
+	{80, [9]float64{56, 38.25, 23, 17.25, 15.81, 14.81, 14, 16, 18}},
+	{85, [9]float64{52, 34.5, 22, 16.5, 15.625, 14.625, 14, 16, 18}},
+	{90, [9]float64{48, 32, 21, 16, 15.5, 14.5, 14, 16, 18}},
+	{95, [9]float64{45, 28, 19.5, 15.5, 15, 14, 13.5, 16, 18}},
+	{100, [9]float64{42, 26.5, 18.5, 15, 14.5, 13.5, 13, 16, 18}},
+	{105, [9]float64{39, 25, 18, 14.5, 14, 13, 12, 16, 18}},
+	{110, [9]float64{36, 24, 18, 14, 13, 12, 11, 16, 18}},
+	{115, [9]float64{34.5, 22.5, 17.25, 12.5, 11.875, 11.25, 10.625, 14.5, 16.5}},
+	{120, [9]float64{33, 21, 16.5, 11, 10.75, 10.5, 10.25, 13, 15}},
+	{125, [9]float64{32, 20, 16, 10, 10, 10, 10, 12, 14}},
+}
+
+// APCAFontSize is the smallest recommended font size for one weight
+type APCAFontSize struct {
+	Weight  int
+	Size    float64 // px; 0 when the weight should not be used for text
+	NonText bool    // the contrast only suits non-text elements
+}
+
+// apcaFontLookup returns the minimum font size per weight for an Lc value,
+// using the table row at or below |Lc|
+func apcaFontLookup(lc float64) []APCAFontSize {
+	lc = math.Abs(lc)
+	row := apcaFontTable[0]
+	for _, r := range apcaFontTable {
+		if r.lc <= lc {
+			row = r
+		}
+	}
+
+	sizes := make([]APCAFontSize, len(APCAFontWeights))
+	for i, weight := range APCAFontWeights {
+		sizes[i] = APCAFontSize{Weight: weight}
+		switch size := row.sizes[i]; size {
+		case apcaFontProhibited:
+		case apcaFontNonText:
+			sizes[i].NonText = true
+		default:
+			sizes[i].Size = size
+		}
+	}
+	return sizes
+}
+
+// getAPCALevel describes what an Lc value is enough for, following the APCA
+// simple-mode guidance
+func getAPCALevel(lc float64) string {
+	switch lc = math.Abs(lc); {
+	case lc >= 90:
+		return "preferred for body text"
+	case lc >= 75:
+		return "minimum for body text"
+	case lc >= 60:
+		return "minimum for content text"
+	case lc >= 45:
+		return "minimum for large text and headlines"
+	case lc >= 30:
+		return "minimum for any text (placeholder, disabled)"
+	case lc >= 15:
+		return "non-text elements only"
+	default:
+		return "not readable"
+	}
+}
+
+// formatAPCAFontSizes formats the font lookup as "weight: size" pairs
+func formatAPCAFontSizes(sizes []APCAFontSize) string {
+	parts := make([]string, len(sizes))
+	for i, s := range sizes {
+		switch {
+		case s.NonText:
+			parts[i] = fmt.Sprintf("%d: non-text", s.Weight)
+		case s.Size == 0:
+			parts[i] = fmt.Sprintf("%d: -", s.Weight)
+		default:
+			parts[i] = fmt.Sprintf("%d: %spx", s.Weight, strconv.FormatFloat(s.Size, 'f', -1, 64))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
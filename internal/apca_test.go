@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestAPCAContrast checks Lc against the APCA-W3 reference values
+func TestAPCAContrast(t *testing.T) {
+	tests := []struct {
+		text, background string
+		expected         float64
+	}{
+		{"#888", "#fff", 63.056469930209424},
+		{"#fff", "#888", -68.54146436644962},
+		{"#000", "#aaa", 58.146262578561334},
+		{"#aaa", "#000", -56.24113336839742},
+		{"#123", "#def", 91.66830811481679},
+		{"#def", "#123", -93.06770049484275},
+		{"#000", "#fff", 106.04067321268862},
+		{"#fff", "#000", -107.88473318309848},
+		{"#777", "#777", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text+" on "+tt.background, func(t *testing.T) {
+			text, _ := DetectFormat(tt.text)
+			bg, _ := DetectFormat(tt.background)
+			lc := calculateAPCAContrast(text.Color, bg.Color)
+			if math.Abs(lc-tt.expected) > 1e-6 {
+				t.Errorf("expected Lc %v, got %v", tt.expected, lc)
+			}
+		})
+	}
+}
+
+func TestAPCAFontLookup(t *testing.T) {
+	sizes := apcaFontLookup(-63.1)
+	if sizes[3].Weight != 400 || sizes[3].Size != 24 {
+		t.Errorf("expected 24px at weight 400, got %+v", sizes[3])
+	}
+	if sizes[0].Size != 72 {
+		t.Errorf("expected 72px at weight 100, got %+v", sizes[0])
+	}
+
+	sizes = apcaFontLookup(20)
+	for _, s := range sizes {
+		if !s.NonText {
+			t.Errorf("weight %d: expected non-text only at Lc 20", s.Weight)
+		}
+	}
+	sizes = apcaFontLookup(5)
+	for _, s := range sizes {
+		if s.Size != 0 || s.NonText {
+			t.Errorf("weight %d: expected no use at Lc 5", s.Weight)
+		}
+	}
+}
+
+func TestParseContrastAlgorithm(t *testing.T) {
+	if a, err := ParseContrastAlgorithm(""); err != nil || a != ContrastWCAG2 {
+		t.Errorf("expected wcag2 by default, got %s (%v)", a, err)
+	}
+	if a, err := ParseContrastAlgorithm("APCA"); err != nil || a != ContrastAPCA {
+		t.Errorf("expected apca, got %s (%v)", a, err)
+	}
+	if _, err := ParseContrastAlgorithm("wcag3"); err == nil {
+		t.Error("expected error for unknown algorithm")
+	}
+}
+
+func TestFormatComparisonAPCA(t *testing.T) {
+	result, err := CompareColors("#888", "#fff", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result.ContrastAlgorithm = ContrastAPCA
+	basic := FormatComparisonBasic(result)
+	if !strings.Contains(basic, "APCA Contrast: Lc 63.1") || strings.Contains(basic, "Contrast Ratio") {
+		t.Errorf("unexpected APCA output:\n%s", basic)
+	}
+
+	result.ContrastAlgorithm = ContrastBoth
+	detailed := FormatComparisonDetailed(result)
+	for _, s := range []string{"Contrast Ratio: 3.54:1", "APCA Contrast: Lc 63.1", "400: 24px"} {
+		if !strings.Contains(detailed, s) {
+			t.Errorf("detailed output missing %q:\n%s", s, detailed)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ClusterMethod selects how cluster_colors groups colors
+type ClusterMethod string
+
+const (
+	ClusterThreshold     ClusterMethod = "threshold"     // agglomerative until clusters would exceed a ΔE diameter
+	ClusterAgglomerative ClusterMethod = "agglomerative" // agglomerative down to a number of clusters
+	ClusterKMeans        ClusterMethod = "kmeans"        // k-means in OKLab
+)
+
+// Clustering limits
+const (
+	MaxClusterColors        = 500
+	maxKMeansIterations     = 100
+	DefaultClusterThreshold = DeltaEIndistinguishable
+)
+
+// GetClusterMethods returns the supported clustering methods
+func GetClusterMethods() []string {
+	return []string{string(ClusterThreshold), string(ClusterAgglomerative), string(ClusterKMeans)}
+}
+
+// ParseClusterMethod validates a clustering method name; empty selects threshold
+func ParseClusterMethod(name string) (ClusterMethod, error) {
+	switch m := ClusterMethod(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return ClusterThreshold, nil
+	case ClusterThreshold, ClusterAgglomerative, ClusterKMeans:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid cluster method: %s (supported: %s)", name, strings.Join(GetClusterMethods(), ", "))
+	}
+}
+
+// ClusterMember is one input color of a cluster
+type ClusterMember struct {
+	Index  int     // position in the input list
+	DeltaE float64 // OKLCH ΔE to the cluster medoid
+}
+
+// ColorCluster is a group of perceptually close colors
+type ColorCluster struct {
+	Medoid    int    // input index of the member closest to all others
+	Color     string // the medoid in the requested format
+	HEX       string
+	Members   []ClusterMember // in input order, the medoid included
+	MaxDeltaE float64         // largest ΔE from the medoid
+}
+
+// ClusterResult holds the clusters of a color list
+type ClusterResult struct {
+	Colors    []ColorData
+	Method    ClusterMethod
+	Threshold float64 // ΔE diameter for the threshold method
+	Count     int     // requested clusters for agglomerative and kmeans
+	Distinct  int     // distinct input colors, which caps the kmeans clusters
+	Clusters  []ColorCluster
+}
+
+// ClusterColors groups colors by perceptual distance in OKLab, where the
+// Euclidean distance is calculateOKLCHDeltaE. The threshold method merges
+// clusters (complete linkage) while every pair in the merged cluster stays
+// within threshold ΔE (0 merges exact duplicates only); agglomerative merges the same way down to count
+// clusters; kmeans runs Lloyd's algorithm from farthest-point seeds. Each
+// cluster is represented by its medoid. Clusters are ordered largest first.
+func ClusterColors(colors []string, method ClusterMethod, threshold float64, count int, targetFormat string, opts ConvertOptions) (*ClusterResult, error) {
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("at least one color is required")
+	}
+	if len(colors) > MaxClusterColors {
+		return nil, fmt.Errorf("at most %d colors can be clustered, got %d", MaxClusterColors, len(colors))
+	}
+	method, err := ParseClusterMethod(string(method))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ClusterResult{Method: method}
+	points := make([][3]float64, len(colors))
+	for i, color := range colors {
+		data, err := DetectFormat(color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q: %w", color, err)
+		}
+		result.Colors = append(result.Colors, data)
+		points[i] = colorToOKLab(data.Color)
+	}
+
+	var groups [][]int
+	switch method {
+	case ClusterThreshold:
+		if threshold < 0 {
+			return nil, fmt.Errorf("threshold must not be negative, got %g", threshold)
+		}
+		result.Threshold = threshold
+		groups = agglomerate(points, 1, threshold)
+	default:
+		if count < 1 || count > len(colors) {
+			return nil, fmt.Errorf("count must be between 1 and the number of colors (%d), got %d", len(colors), count)
+		}
+		result.Count = count
+		if method == ClusterKMeans {
+			result.Distinct = countDistinct(points)
+			groups = kMeans(points, count)
+		} else {
+			groups = agglomerate(points, count, math.Inf(1))
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i]) != len(groups[j]) {
+			return len(groups[i]) > len(groups[j])
+		}
+		return groups[i][0] < groups[j][0]
+	})
+
+	for _, group := range groups {
+		cluster := ColorCluster{Medoid: clusterMedoid(points, group)}
+		for _, i := range group {
+			deltaE := oklabDistance(points[i], points[cluster.Medoid])
+			cluster.Members = append(cluster.Members, ClusterMember{Index: i, DeltaE: deltaE})
+			cluster.MaxDeltaE = math.Max(cluster.MaxDeltaE, deltaE)
+		}
+		c := result.Colors[cluster.Medoid].Color
+		cluster.HEX = formatHEX(c.R, c.G, c.B, c.A)
+		cluster.Color, err = FormatColor(result.Colors[cluster.Medoid], targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Clusters = append(result.Clusters, cluster)
+	}
+
+	return result, nil
+}
+
+// oklabDistance is the Euclidean distance between two OKLab points
+func oklabDistance(p, q [3]float64) float64 {
+	d0, d1, d2 := p[0]-q[0], p[1]-q[1], p[2]-q[2]
+	return math.Sqrt(d0*d0 + d1*d1 + d2*d2)
+}
+
+// agglomerate merges the closest pair of clusters under complete linkage until
+// count clusters remain or the closest pair is farther apart than maxDistance.
+// Groups list input indexes in ascending order.
+func agglomerate(points [][3]float64, count int, maxDistance float64) [][]int {
+	n := len(points)
+	groups := make([][]int, n)
+	dist := make([][]float64, n)
+	for i := range points {
+		groups[i] = []int{i}
+		dist[i] = make([]float64, n)
+		for j := range points {
+			dist[i][j] = oklabDistance(points[i], points[j])
+		}
+	}
+
+	active := n
+	for active > count {
+		bi, bj, best := -1, -1, math.Inf(1)
+		for i := 0; i < n; i++ {
+			if groups[i] == nil {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if groups[j] != nil && dist[i][j] < best {
+					bi, bj, best = i, j, dist[i][j]
+				}
+			}
+		}
+		if bi < 0 || best > maxDistance {
+			break
+		}
+
+		// Complete linkage: the merged cluster is as far as its farthest member
+		for k := 0; k < n; k++ {
+			d := math.Max(dist[bi][k], dist[bj][k])
+			dist[bi][k], dist[k][bi] = d, d
+		}
+		groups[bi] = append(groups[bi], groups[bj]...)
+		sort.Ints(groups[bi])
+		groups[bj] = nil
+		active--
+	}
+
+	var result [][]int
+	for _, g := range groups {
+		if g != nil {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// kMeans runs Lloyd's algorithm with k centers seeded deterministically: the
+// medoid of all points, then repeatedly the point farthest from every center.
+// k is capped at the number of distinct points, so no two seeds coincide.
+func kMeans(points [][3]float64, k int) [][]int {
+	k = min(k, countDistinct(points))
+
+	all := make([]int, len(points))
+	for i := range all {
+		all[i] = i
+	}
+	centers := [][3]float64{points[clusterMedoid(points, all)]}
+	for len(centers) < k {
+		far, farDist := 0, -1.0
+		for i, p := range points {
+			d := math.Inf(1)
+			for _, c := range centers {
+				d = math.Min(d, oklabDistance(p, c))
+			}
+			if d > farDist {
+				far, farDist = i, d
+			}
+		}
+		centers = append(centers, points[far])
+	}
+
+	assign := make([]int, len(points))
+	for iter := 0; iter < maxKMeansIterations; iter++ {
+		changed := iter == 0
+		for i, p := range points {
+			nearest, nearestDist := 0, math.Inf(1)
+			for c, center := range centers {
+				if d := oklabDistance(p, center); d < nearestDist {
+					nearest, nearestDist = c, d
+				}
+			}
+			if assign[i] != nearest {
+				assign[i] = nearest
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][4]float64, k)
+		for i, p := range points {
+			s := &sums[assign[i]]
+			s[0] += p[0]
+			s[1] += p[1]
+			s[2] += p[2]
+			s[3]++
+		}
+		for c, s := range sums {
+			if s[3] > 0 {
+				centers[c] = [3]float64{s[0] / s[3], s[1] / s[3], s[2] / s[3]}
+			}
+		}
+	}
+
+	groups := make([][]int, k)
+	for i, c := range assign {
+		groups[c] = append(groups[c], i)
+	}
+	var result [][]int
+	for _, g := range groups {
+		if len(g) > 0 {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// countDistinct returns the number of distinct points
+func countDistinct(points [][3]float64) int {
+	seen := make(map[[3]float64]bool, len(points))
+	for _, p := range points {
+		seen[p] = true
+	}
+	return len(seen)
+}
+
+// clusterMedoid returns the member with the smallest total distance to the
+// other members; ties go to the earliest input
+func clusterMedoid(points [][3]float64, members []int) int {
+	best, bestSum := members[0], math.Inf(1)
+	for _, i := range members {
+		sum := 0.0
+		for _, j := range members {
+			sum += oklabDistance(points[i], points[j])
+		}
+		if sum < bestSum {
+			best, bestSum = i, sum
+		}
+	}
+	return best
+}
+
+// FormatClusters formats color clusters as text
+func FormatClusters(result *ClusterResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%d clusters from %d colors", len(result.Clusters), len(result.Colors)))
+	switch result.Method {
+	case ClusterThreshold:
+		builder.WriteString(fmt.Sprintf(" (threshold: all members within ΔE %s of each other)\n", strconv.FormatFloat(result.Threshold, 'f', -1, 64)))
+	case ClusterAgglomerative:
+		builder.WriteString(" (agglomerative, complete linkage)\n")
+	case ClusterKMeans:
+		if result.Distinct < result.Count {
+			builder.WriteString(fmt.Sprintf(" (k-means in OKLab; %d requested, capped at the %d distinct colors)\n", result.Count, result.Distinct))
+		} else {
+			builder.WriteString(" (k-means in OKLab)\n")
+		}
+	}
+
+	for n, cluster := range result.Clusters {
+		color := cluster.Color
+		if cluster.Color != cluster.HEX {
+			color = fmt.Sprintf("%s (%s)", cluster.Color, cluster.HEX)
+		}
+		if len(cluster.Members) == 1 {
+			builder.WriteString(fmt.Sprintf("\nCluster %d: %s, 1 color\n", n+1, color))
+		} else {
+			builder.WriteString(fmt.Sprintf("\nCluster %d: %s, %d colors, max ΔE %.4f\n", n+1, color, len(cluster.Members), cluster.MaxDeltaE))
+		}
+		for _, m := range cluster.Members {
+			original := result.Colors[m.Index].Original
+			if m.Index == cluster.Medoid {
+				builder.WriteString(fmt.Sprintf("  %s (medoid)\n", original))
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("  %s  ΔE %.4f\n", original, m.DeltaE))
+		}
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
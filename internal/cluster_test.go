@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+var clusterInput = []string{"#777777", "#787878", "#7a7a7a", "#767677", "#3b82f6", "#3c83f5", "#ef4444", "#888888"}
+
+func TestClusterColorsThreshold(t *testing.T) {
+	result, err := ClusterColors(clusterInput, "", DefaultClusterThreshold, 0, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Method != ClusterThreshold || result.Threshold != DefaultClusterThreshold {
+		t.Errorf("unexpected method %s, threshold %g", result.Method, result.Threshold)
+	}
+	if len(result.Clusters) != 4 {
+		t.Fatalf("expected 4 clusters, got %d:\n%s", len(result.Clusters), FormatClusters(result))
+	}
+
+	grays := result.Clusters[0]
+	if grays.HEX != "#787878" || grays.Medoid != 1 || len(grays.Members) != 4 {
+		t.Errorf("expected the grays around #787878 first, got %+v", grays)
+	}
+	// Complete linkage keeps every pair within the threshold
+	for _, cluster := range result.Clusters {
+		for _, a := range cluster.Members {
+			for _, b := range cluster.Members {
+				d := calculateOKLCHDeltaE(result.Colors[a.Index].Color, result.Colors[b.Index].Color)
+				if d > result.Threshold {
+					t.Errorf("%s and %s are %.4f apart", clusterInput[a.Index], clusterInput[b.Index], d)
+				}
+			}
+		}
+	}
+
+	// A wider threshold pulls #888888 in with the other grays
+	result, err = ClusterColors(clusterInput, ClusterThreshold, 0.08, 0, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Clusters) != 3 || len(result.Clusters[0].Members) != 5 {
+		t.Errorf("expected 3 clusters with 5 grays, got:\n%s", FormatClusters(result))
+	}
+
+	// A zero threshold merges exact duplicates only
+	result, err = ClusterColors([]string{"#3b82f6", "rgb(59, 130, 246)", "#3c83f5", "#3B82F6"}, ClusterThreshold, 0, 0, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Threshold != 0 || len(result.Clusters) != 2 || len(result.Clusters[0].Members) != 3 {
+		t.Errorf("expected the three duplicates merged and #3c83f5 alone, got:\n%s", FormatClusters(result))
+	}
+}
+
+func TestClusterColorsCount(t *testing.T) {
+	for _, method := range []ClusterMethod{ClusterAgglomerative, ClusterKMeans} {
+		t.Run(string(method), func(t *testing.T) {
+			result, err := ClusterColors(clusterInput, method, 0, 3, "hex", ConvertOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Clusters) != 3 {
+				t.Fatalf("expected 3 clusters, got %d", len(result.Clusters))
+			}
+			expected := []struct {
+				hex  string
+				size int
+			}{{"#787878", 5}, {"#3B82F6", 2}, {"#EF4444", 1}}
+			total := 0
+			for i, e := range expected {
+				c := result.Clusters[i]
+				if c.HEX != e.hex || len(c.Members) != e.size {
+					t.Errorf("cluster %d: expected %s with %d members, got %s with %d", i, e.hex, e.size, c.HEX, len(c.Members))
+				}
+				total += len(c.Members)
+			}
+			if total != len(clusterInput) {
+				t.Errorf("expected every color in a cluster, got %d", total)
+			}
+		})
+	}
+}
+
+func TestClusterColorsKMeansDuplicates(t *testing.T) {
+	// Four clusters are requested from two distinct colors: k is capped
+	// instead of seeding duplicate centers
+	result, err := ClusterColors([]string{"#ff0000", "red", "#0000ff", "blue", "rgb(255, 0, 0)"}, ClusterKMeans, 0, 4, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Clusters) != 2 || len(result.Clusters[0].Members) != 3 || len(result.Clusters[1].Members) != 2 {
+		t.Fatalf("expected red (3) and blue (2) clusters, got:\n%s", FormatClusters(result))
+	}
+	if text := FormatClusters(result); !strings.Contains(text, "4 requested, capped at the 2 distinct colors") {
+		t.Errorf("expected the cap in the output:\n%s", text)
+	}
+}
+
+func TestFormatClusters(t *testing.T) {
+	result, err := ClusterColors([]string{"#777777", "#787878", "#ff0000"}, "", DefaultClusterThreshold, 0, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := FormatClusters(result)
+	for _, want := range []string{"2 clusters from 3 colors", "#777777 (medoid)", "#787878  ΔE 0.0034", "Cluster 2: #FF0000, 1 color"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestClusterColorsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		colors    []string
+		method    ClusterMethod
+		threshold float64
+		count     int
+	}{
+		{"no colors", nil, "", 0, 0},
+		{"invalid color", []string{"notacolor"}, "", 0, 0},
+		{"invalid method", clusterInput, "dbscan", 0, 0},
+		{"negative threshold", clusterInput, ClusterThreshold, -1, 0},
+		{"missing count", clusterInput, ClusterKMeans, 0, 0},
+		{"count above colors", clusterInput, ClusterAgglomerative, 0, 9},
+		{"too many colors", make([]string, MaxClusterColors+1), "", 0, 0},
+	}
+	for _, tt := range tests {
+		if _, err := ClusterColors(tt.colors, tt.method, tt.threshold, tt.count, "hex", ConvertOptions{}); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -18,17 +18,30 @@ const (
 // ComparisonResult contains detailed comparison metrics between two colors
 type ComparisonResult struct {
 	Color1, Color2 ColorData
-	PerceptualDiff float64 // OKLCH ΔE (0-1+)
-	Verdict        VerdictType
-	HueDiff        float64 // 0-360° (HSL-based)
-	LightnessDiff  float64 // 0-100% (HSL-based)
-	SaturationDiff float64 // 0-100% (HSL-based)
-	ContrastRatio  float64 // WCAG ratio (1-21)
+	PerceptualDiff float64 // ΔE under Metric (OKLCH ΔE is 0-1+)
+	Metric         DeltaEMetric
+	Verdict        VerdictType // calibrated to the thresholds of Metric
+	HueDiff        float64     // 0-360° (HSL-based)
+	LightnessDiff  float64     // 0-100% (HSL-based)
+	SaturationDiff float64     // 0-100% (HSL-based)
+	ContrastRatio  float64     // WCAG ratio (1-21)
 	WCAGGrade      string
+
+	// APCA treats color1 as text on color2 as background
+	APCAContrast      float64 // Lc (about -108 to 106)
+	APCALevel         string
+	APCAFontSizes     []APCAFontSize
+	ContrastAlgorithm ContrastAlgorithm // contrast measure shown by the formatters (default: wcag2)
 }
 
-// CompareColors compares two colors for perceptual similarity, component differences, and contrast ratio
-func CompareColors(color1, color2 string) (*ComparisonResult, error) {
+// CompareColors compares two colors for perceptual similarity, component differences, and contrast ratio.
+// The perceptual difference uses the given metric, with color1 as the reference; empty selects deltaEOK.
+func CompareColors(color1, color2 string, metric DeltaEMetric) (*ComparisonResult, error) {
+	metric, err := ParseDeltaEMetric(string(metric))
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse both colors using existing DetectFormat
 	data1, err := DetectFormat(color1)
 	if err != nil {
@@ -41,8 +54,8 @@ func CompareColors(color1, color2 string) (*ComparisonResult, error) {
 	}
 
 	// Calculate all metrics
-	deltaE := calculateOKLCHDeltaE(data1.Color, data2.Color)
-	verdict := determineVerdict(deltaE)
+	deltaE := calculateDeltaE(metric, data1.Linear, data2.Linear)
+	verdict := determineMetricVerdict(metric, deltaE)
 
 	h1, s1, l1 := rgbToHSL(data1.Color.R, data1.Color.G, data1.Color.B)
 	h2, s2, l2 := rgbToHSL(data2.Color.R, data2.Color.G, data2.Color.B)
@@ -53,17 +66,24 @@ func CompareColors(color1, color2 string) (*ComparisonResult, error) {
 
 	contrast := calculateContrastRatio(data1.Color, data2.Color)
 	wcagGrade := getWCAGGrade(contrast)
+	apca := calculateAPCAContrast(data1.Color, data2.Color)
 
 	return &ComparisonResult{
 		Color1:         data1,
 		Color2:         data2,
 		PerceptualDiff: deltaE,
+		Metric:         metric,
 		Verdict:        verdict,
 		HueDiff:        hueDiff,
 		LightnessDiff:  lightnessDiff,
 		SaturationDiff: saturationDiff,
 		ContrastRatio:  contrast,
 		WCAGGrade:      wcagGrade,
+
+		APCAContrast:      apca,
+		APCALevel:         getAPCALevel(apca),
+		APCAFontSizes:     apcaFontLookup(apca),
+		ContrastAlgorithm: ContrastWCAG2,
 	}, nil
 }
 
@@ -147,38 +167,58 @@ func getWCAGGrade(contrast float64) string {
 
 // FormatComparisonBasic formats comparison result with basic information
 func FormatComparisonBasic(result *ComparisonResult) string {
-	return fmt.Sprintf(
+	text := fmt.Sprintf(
 		"Color Comparison: %s vs %s\n"+
-			"Perceptual Difference: %.3f ΔE\n"+
-			"Verdict: %s\n"+
-			"Contrast Ratio: %.2f:1 (%s)",
+			"Perceptual Difference: %.3f %s\n"+
+			"Verdict: %s",
 		result.Color1.Original, result.Color2.Original,
-		result.PerceptualDiff,
+		result.PerceptualDiff, deltaELabel(result.Metric),
 		result.Verdict,
-		result.ContrastRatio, result.WCAGGrade,
 	)
+	if result.ContrastAlgorithm != ContrastAPCA {
+		text += fmt.Sprintf("\nContrast Ratio: %.2f:1 (%s)", result.ContrastRatio, result.WCAGGrade)
+	}
+	if result.ContrastAlgorithm == ContrastAPCA || result.ContrastAlgorithm == ContrastBoth {
+		text += fmt.Sprintf("\nAPCA Contrast: Lc %.1f (%s)", result.APCAContrast, result.APCALevel)
+	}
+	return text
 }
 
 // FormatComparisonDetailed formats comparison result with detailed breakdown
 func FormatComparisonDetailed(result *ComparisonResult) string {
-	return fmt.Sprintf(
+	text := fmt.Sprintf(
 		"Color Comparison: %s (%s) vs %s (%s)\n\n"+
-			"Perceptual Difference: %.3f ΔE\n"+
+			"Perceptual Difference: %.3f %s\n"+
 			"Verdict: %s\n\n"+
 			"Component Breakdown:\n"+
 			"  Hue Difference: %.1f°\n"+
 			"  Lightness Difference: %.1f%%\n"+
-			"  Saturation Difference: %.1f%%\n\n"+
-			"Contrast Ratio: %.2f:1\n"+
-			"WCAG Grade: %s",
+			"  Saturation Difference: %.1f%%",
 		result.Color1.Original, result.Color1.Format,
 		result.Color2.Original, result.Color2.Format,
-		result.PerceptualDiff,
+		result.PerceptualDiff, deltaELabel(result.Metric),
 		result.Verdict,
 		result.HueDiff,
 		result.LightnessDiff,
 		result.SaturationDiff,
-		result.ContrastRatio,
-		result.WCAGGrade,
 	)
+	if result.ContrastAlgorithm != ContrastAPCA {
+		text += fmt.Sprintf(
+			"\n\nContrast Ratio: %.2f:1\n"+
+				"WCAG Grade: %s",
+			result.ContrastRatio,
+			result.WCAGGrade,
+		)
+	}
+	if result.ContrastAlgorithm == ContrastAPCA || result.ContrastAlgorithm == ContrastBoth {
+		text += fmt.Sprintf(
+			"\n\nAPCA Contrast: Lc %.1f (%s text on %s)\n"+
+				"APCA Level: %s\n"+
+				"Minimum Font Size by Weight: %s",
+			result.APCAContrast, result.Color1.Original, result.Color2.Original,
+			result.APCALevel,
+			formatAPCAFontSizes(result.APCAFontSizes),
+		)
+	}
+	return text
 }
@@ -20,7 +20,7 @@ func TestCompareColors_Identical(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -62,7 +62,7 @@ func TestCompareColors_Indistinguishable(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -93,7 +93,7 @@ func TestCompareColors_SlightlyDifferent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -124,7 +124,7 @@ func TestCompareColors_Different(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -158,7 +158,7 @@ func TestCompareColors_ContrastRatio(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -238,7 +238,7 @@ func TestCompareColors_ComponentDifferences(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -276,7 +276,7 @@ func TestCompareColors_InvalidInput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := CompareColors(tt.color1, tt.color2)
+			_, err := CompareColors(tt.color1, tt.color2, "")
 			if err == nil {
 				t.Error("Expected error for invalid input, got nil")
 			}
@@ -298,7 +298,7 @@ func TestCompareColors_DifferentFormats(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := CompareColors(tt.color1, tt.color2)
+			result, err := CompareColors(tt.color1, tt.color2, "")
 			if err != nil {
 				t.Fatalf("CompareColors() error = %v", err)
 			}
@@ -316,7 +316,7 @@ func TestCompareColors_DifferentFormats(t *testing.T) {
 }
 
 func TestFormatComparisonBasic(t *testing.T) {
-	result, err := CompareColors("#FF0000", "#00FF00")
+	result, err := CompareColors("#FF0000", "#00FF00", "")
 	if err != nil {
 		t.Fatalf("CompareColors() error = %v", err)
 	}
@@ -342,7 +342,7 @@ func TestFormatComparisonBasic(t *testing.T) {
 }
 
 func TestFormatComparisonDetailed(t *testing.T) {
-	result, err := CompareColors("#FF0000", "#00FF00")
+	result, err := CompareColors("#FF0000", "#00FF00", "")
 	if err != nil {
 		t.Fatalf("CompareColors() error = %v", err)
 	}
@@ -11,6 +11,11 @@ const (
 	OKLCH_L_Percent float64 = 100.0
 	OKLCH_C_Max     float64 = 0.4
 	OKLCH_H_Max     float64 = 360.0
+	OKLAB_AB_Max    float64 = 0.4   // 100% in oklab() a/b
+	LAB_L_Max       float64 = 100.0 // 100% in lab()/lch() lightness
+	LAB_AB_Max      float64 = 125.0 // 100% in lab() a/b
+	LCH_C_Max       float64 = 150.0 // 100% in lch() chroma
+	HCT_T_Max       float64 = 100.0 // tone (CIE L*) in hct()
 	AlphaMin        float64 = 0.0
 	AlphaMax        float64 = 1.0
 )
@@ -25,6 +30,18 @@ const (
 	sRGBGammaSubtract    = 0.055
 )
 
+// Transfer function constants for predefined RGB spaces
+const (
+	rec2020Alpha            = 1.09929682680944
+	rec2020Beta             = 0.018053968510807
+	rec2020Power            = 0.45
+	rec2020LinearFactor     = 4.5
+	a98RGBGamma             = 563.0 / 256.0
+	proPhotoGamma           = 1.8
+	proPhotoLinearThreshold = 1.0 / 512.0
+	proPhotoLinearFactor    = 16.0
+)
+
 // LAB conversion constants
 const (
 	labK = 29.0 * 29.0 * 29.0 / (3.0 * 3.0 * 3.0) // ≈ 903.2962962962963
@@ -49,12 +66,44 @@ const (
 	rgbBValueIdx   = 5
 	rgbBPercentIdx = 6
 	rgbAValueIdx   = 7
+	rgbAPercentIdx = 8
+	// HSL groups
+	hslAValueIdx   = 4
+	hslAPercentIdx = 5
 	// OKLCH groups
 	oklchLValueIdx   = 1
 	oklchLPercentIdx = 2
 	oklchCValueIdx   = 3
 	oklchHValueIdx   = 4
 	oklchAValueIdx   = 5
+	oklchAPercentIdx = 6
+	// OKLab groups
+	oklabLValueIdx   = 1
+	oklabLPercentIdx = 2
+	oklabAValueIdx   = 3
+	oklabAPercentIdx = 4
+	oklabBValueIdx   = 5
+	oklabBPercentIdx = 6
+	oklabAlphaIdx    = 7
+	oklabAlphaPctIdx = 8
+	// LCH groups
+	lchLValueIdx   = 1
+	lchLPercentIdx = 2
+	lchCValueIdx   = 3
+	lchCPercentIdx = 4
+	lchHValueIdx   = 5
+	lchAValueIdx   = 6
+	lchAPercentIdx = 7
+	// color() groups
+	colorSpaceIdx     = 1
+	colorC1ValueIdx   = 2
+	colorC1PercentIdx = 3
+	colorC2ValueIdx   = 4
+	colorC2PercentIdx = 5
+	colorC3ValueIdx   = 6
+	colorC3PercentIdx = 7
+	colorAValueIdx    = 8
+	colorAPercentIdx  = 9
 )
 
 // Comparison thresholds (based on OKLCH ΔE research)
@@ -64,6 +113,13 @@ const (
 	DeltaESlightlyDifferent float64 = 0.10 // Noticeable but similar
 )
 
+// Gamut mapping (CSS Color 4)
+const (
+	gamutMappingJND     = 0.02   // deltaEOK just noticeable difference
+	gamutMappingEpsilon = 0.0001 // chroma search precision
+	gamutTolerance      = 0.00001
+)
+
 // WCAG contrast thresholds
 const (
 	WCAGAAANormal float64 = 7.0
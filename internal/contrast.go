@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContrastTarget names a WCAG 2 contrast level
+type ContrastTarget string
+
+const (
+	ContrastAA      ContrastTarget = "AA"       // normal text, 4.5:1
+	ContrastAAA     ContrastTarget = "AAA"      // normal text, 7:1
+	ContrastAALarge ContrastTarget = "AA-large" // large text, 3:1
+)
+
+// contrastTargetRatios maps each WCAG level to its minimum contrast ratio
+var contrastTargetRatios = map[ContrastTarget]float64{
+	ContrastAA:      WCAGAANormal,
+	ContrastAAA:     WCAGAAANormal,
+	ContrastAALarge: WCAGAALarge,
+}
+
+// Contrast ratio range
+const (
+	ContrastRatioMin float64 = 1.0
+	ContrastRatioMax float64 = 21.0
+)
+
+// GetContrastTargets returns the named WCAG contrast levels
+func GetContrastTargets() []string {
+	return []string{string(ContrastAA), string(ContrastAAA), string(ContrastAALarge)}
+}
+
+// ParseContrastTarget returns the minimum contrast ratio of a WCAG level name
+// or of a numeric ratio such as "4.5" or "4.5:1"; empty selects AA
+func ParseContrastTarget(target string) (float64, error) {
+	t := strings.TrimSpace(target)
+	if t == "" {
+		return WCAGAANormal, nil
+	}
+	for name, ratio := range contrastTargetRatios {
+		if strings.EqualFold(t, string(name)) {
+			return ratio, nil
+		}
+	}
+
+	ratio, err := strconv.ParseFloat(strings.TrimSuffix(t, ":1"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid contrast target: %s (supported: %s or a ratio between %g and %g)", target, strings.Join(GetContrastTargets(), ", "), ContrastRatioMin, ContrastRatioMax)
+	}
+	if ratio < ContrastRatioMin || ratio > ContrastRatioMax {
+		return 0, fmt.Errorf("contrast ratio must be between %g and %g, got %g", ContrastRatioMin, ContrastRatioMax, ratio)
+	}
+	return ratio, nil
+}
+
+// ContrastSide selects which color of a pair fix_contrast changes
+type ContrastSide string
+
+const (
+	ContrastForeground ContrastSide = "foreground"
+	ContrastBackground ContrastSide = "background"
+)
+
+// GetContrastSides returns the colors fix_contrast can change
+func GetContrastSides() []string {
+	return []string{string(ContrastForeground), string(ContrastBackground)}
+}
+
+// ParseContrastSide validates a side name; empty selects the foreground
+func ParseContrastSide(name string) (ContrastSide, error) {
+	switch s := ContrastSide(strings.ToLower(strings.TrimSpace(name))); s {
+	case "":
+		return ContrastForeground, nil
+	case ContrastForeground, ContrastBackground:
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid side: %s (supported: %s)", name, strings.Join(GetContrastSides(), ", "))
+	}
+}
+
+// FixContrastResult holds the closest color that reaches a contrast target
+type FixContrastResult struct {
+	Foreground, Background ColorData
+	Adjusted               ContrastSide
+	Target                 float64
+	OriginalRatio          float64
+	Color                  string  // the adjusted color in the requested format
+	HEX                    string  // sRGB hex of the adjusted color
+	Ratio                  float64 // WCAG contrast ratio reached
+	DeltaE                 float64 // OKLCH ΔE from the original color
+	Passes                 bool    // false when no lightness reaches the target
+}
+
+// FixContrast finds the color closest to the foreground (or background) that
+// reaches the target contrast ratio against the other color. It searches OKLCH
+// lightness, lighter and darker, keeping the hue and as much chroma as sRGB
+// allows, and returns whichever passing color has the smaller ΔE. When neither
+// direction reaches the target, the color with the highest contrast is returned.
+func FixContrast(foreground, background string, target float64, side ContrastSide, targetFormat string, opts ConvertOptions) (*FixContrastResult, error) {
+	fg, err := DetectFormat(foreground)
+	if err != nil {
+		return nil, fmt.Errorf("invalid foreground color: %w", err)
+	}
+	bg, err := DetectFormat(background)
+	if err != nil {
+		return nil, fmt.Errorf("invalid background color: %w", err)
+	}
+	side, err = ParseContrastSide(string(side))
+	if err != nil {
+		return nil, err
+	}
+
+	adjusted, fixed := fg, bg
+	if side == ContrastBackground {
+		adjusted, fixed = bg, fg
+	}
+
+	result := &FixContrastResult{
+		Foreground:    fg,
+		Background:    bg,
+		Adjusted:      side,
+		Target:        target,
+		OriginalRatio: calculateContrastRatio(fg.Color, bg.Color),
+	}
+
+	best := adjusted.Color
+	if result.OriginalRatio < target {
+		best = fixContrastSearch(adjusted.Linear, fixed.Color, target)
+	}
+	lc := best.Linear()
+
+	result.Color, err = FormatColor(ColorData{Color: best, Linear: lc}, targetFormat, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.HEX = formatHEX(best.R, best.G, best.B, best.A)
+	result.Ratio = calculateContrastRatio(best, fixed.Color)
+	result.DeltaE = calculateOKLCHDeltaE(adjusted.Color, best)
+	result.Passes = result.Ratio >= target
+
+	return result, nil
+}
+
+// fixContrastSearch returns the closest 8-bit sRGB color to lc, varying only
+// OKLCH lightness, whose contrast against fixed reaches the target
+func fixContrastSearch(lc LinearColor, fixed Color, target float64) Color {
+	l, c, h := linearToOKLCH(lc.R, lc.G, lc.B)
+	fixedLum := calculateRelativeLuminance(fixed)
+
+	colorAt := func(light float64) Color {
+		r, g, b := oklchToLinear(light, c, h)
+		s := sRGBGamutMap(LinearColor{R: r, G: g, B: b, A: lc.A}).SRGB()
+		// Check the color that will actually be written out
+		return Color{R: math.Round(s.R), G: math.Round(s.G), B: math.Round(s.B), A: s.A}
+	}
+	passes := func(light float64, lighter bool) bool {
+		col := colorAt(light)
+		if lighter != (calculateRelativeLuminance(col) > fixedLum) {
+			return false
+		}
+		return calculateContrastRatio(col, fixed) >= target
+	}
+
+	// Lighter: the smallest passing lightness; darker: the largest
+	var candidates []Color
+	if passes(OKLCH_L_Max, true) {
+		lo, hi := l, OKLCH_L_Max
+		for i := 0; i < 40; i++ {
+			mid := (lo + hi) / 2
+			if passes(mid, true) {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		candidates = append(candidates, colorAt(hi))
+	}
+	if passes(0, false) {
+		lo, hi := 0.0, l
+		for i := 0; i < 40; i++ {
+			mid := (lo + hi) / 2
+			if passes(mid, false) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		candidates = append(candidates, colorAt(lo))
+	}
+
+	// Without a passing color, fall back to white or black, whichever contrasts more
+	if len(candidates) == 0 {
+		white, black := colorAt(OKLCH_L_Max), colorAt(0)
+		if calculateContrastRatio(white, fixed) >= calculateContrastRatio(black, fixed) {
+			return white
+		}
+		return black
+	}
+
+	original := lc.SRGB()
+	best := candidates[0]
+	for _, cand := range candidates[1:] {
+		if calculateOKLCHDeltaE(original, cand) < calculateOKLCHDeltaE(original, best) {
+			best = cand
+		}
+	}
+	return best
+}
+
+// FormatFixContrast formats a contrast fix as text
+func FormatFixContrast(result *FixContrastResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Foreground: %s\nBackground: %s\n", result.Foreground.Original, result.Background.Original))
+	builder.WriteString(fmt.Sprintf("Target: %.2f:1, original contrast: %.2f:1 (%s)\n\n", result.Target, result.OriginalRatio, getWCAGGrade(result.OriginalRatio)))
+
+	switch {
+	case result.OriginalRatio >= result.Target:
+		builder.WriteString("The pair already meets the target; no change needed.\n")
+	case !result.Passes:
+		builder.WriteString(fmt.Sprintf("No %s lightness reaches the target; the highest contrast is shown.\n", result.Adjusted))
+	}
+
+	color := result.Color
+	if result.Color != result.HEX {
+		color = fmt.Sprintf("%s (%s)", result.Color, result.HEX)
+	}
+	builder.WriteString(fmt.Sprintf("Adjusted %s: %s\n", result.Adjusted, color))
+	builder.WriteString(fmt.Sprintf("Contrast: %.2f:1 (%s)\n", result.Ratio, getWCAGGrade(result.Ratio)))
+	builder.WriteString(fmt.Sprintf("ΔE from original: %.4f", result.DeltaE))
+	return builder.String()
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseContrastTarget(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"", 4.5},
+		{"AA", 4.5},
+		{"aaa", 7},
+		{"AA-large", 3},
+		{"5.5", 5.5},
+		{"4.5:1", 4.5},
+	}
+	for _, tt := range tests {
+		ratio, err := ParseContrastTarget(tt.input)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if ratio != tt.expected {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.expected, ratio)
+		}
+	}
+
+	for _, input := range []string{"A", "0.5", "22", "high"} {
+		if _, err := ParseContrastTarget(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestFixContrast(t *testing.T) {
+	tests := []struct {
+		name   string
+		fg, bg string
+		target float64
+		side   ContrastSide
+		hex    string
+	}{
+		{"darken blue on white", "#3b82f6", "#ffffff", WCAGAANormal, ContrastForeground, "#2C72E5"},
+		{"lighten gray on dark", "#777777", "#333333", WCAGAAANormal, ContrastForeground, "#C1C1C1"},
+		{"darken background", "#ffffff", "#3b82f6", WCAGAAANormal, ContrastBackground, "#0452C2"},
+		{"already passes", "#000000", "#ffffff", WCAGAAANormal, ContrastForeground, "#000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FixContrast(tt.fg, tt.bg, tt.target, tt.side, "hex", ConvertOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Color != tt.hex {
+				t.Errorf("expected %s, got %s", tt.hex, result.Color)
+			}
+			if !result.Passes || result.Ratio < tt.target {
+				t.Errorf("expected the target %.2f to pass, got %.2f", tt.target, result.Ratio)
+			}
+		})
+	}
+}
+
+// TestFixContrastKeepsHue checks that only lightness (and chroma, when out of gamut) changes
+func TestFixContrastKeepsHue(t *testing.T) {
+	result, err := FixContrast("#e11d48", "#1f2937", WCAGAANormal, ContrastForeground, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig, _ := DetectFormat("#e11d48")
+	fixed, _ := DetectFormat(result.HEX)
+	_, _, h1 := rgbToOKLCH(orig.Color.R, orig.Color.G, orig.Color.B)
+	_, _, h2 := rgbToOKLCH(fixed.Color.R, fixed.Color.G, fixed.Color.B)
+	if calculateHueDifference(h1, h2) > 2 {
+		t.Errorf("hue moved from %.2f to %.2f", h1, h2)
+	}
+	if math.Abs(result.DeltaE-calculateOKLCHDeltaE(orig.Color, fixed.Color)) > 1e-9 {
+		t.Errorf("unexpected ΔE %.4f", result.DeltaE)
+	}
+}
+
+func TestFixContrastUnreachable(t *testing.T) {
+	result, err := FixContrast("#888888", "#808080", ContrastRatioMax, ContrastForeground, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passes {
+		t.Error("expected the target to be unreachable")
+	}
+	if result.Color != "#000000" {
+		t.Errorf("expected the highest contrast color #000000, got %s", result.Color)
+	}
+}
+
+func TestFixContrastErrors(t *testing.T) {
+	if _, err := FixContrast("nope", "#fff", WCAGAANormal, "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for invalid foreground")
+	}
+	if _, err := FixContrast("#000", "nope", WCAGAANormal, "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for invalid background")
+	}
+	if _, err := FixContrast("#000", "#fff", WCAGAANormal, "border", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for invalid side")
+	}
+}
@@ -180,53 +180,75 @@ func rgbToHSB(r, g, b float64) (h, s, v float64) {
 	return h, s * SaturationMax, v * SaturationMax
 }
 
+// Linear-light conversions
+// Wide color spaces (OKLab, OKLCH, LAB, LCH, XYZ, color()) convert to and from
+// linear-light sRGB without clamping, so out-of-gamut values survive a round
+// trip. Clamping to 0-255 happens only when a gamma-encoded sRGB value is needed.
+
 // oklchToRGB converts OKLCH to RGB
 // l: 0-1, c: 0-0.4, h: 0-360
 // Returns RGB values in 0-255 range
-// Based on formulas from culori library
 func oklchToRGB(l, c, h float64) (r, g, b float64) {
+	return linearToRGB(oklchToLinear(l, c, h))
+}
+
+// oklchToLinear converts OKLCH to linear sRGB
+// l: 0-1, c: 0-0.4, h: 0-360
+// Returns unclamped linear RGB values (0-1 inside the sRGB gamut)
+func oklchToLinear(l, c, h float64) (r, g, b float64) {
 	// Convert OKLCH to OKLab
 	hRad := h * math.Pi / 180
 	a := c * math.Cos(hRad)
 	bVal := c * math.Sin(hRad)
 
+	return oklabToLinear(l, a, bVal)
+}
+
+// oklabToLinear converts OKLab to linear sRGB
+// l: 0-1, a, b: roughly -0.4 to 0.4
+// Based on formulas from culori library
+func oklabToLinear(l, a, bVal float64) (r, g, b float64) {
 	// Convert OKLab to LMS (using culori formulas)
 	L := math.Pow(l+0.3963377773761749*a+0.2158037573099136*bVal, 3)
 	M := math.Pow(l-0.1055613458156586*a-0.0638541728258133*bVal, 3)
 	S := math.Pow(l-0.0894841775298119*a-1.2914855480194092*bVal, 3)
 
 	// Convert LMS to linear RGB (using culori formulas)
-	rLin := 4.0767416360759574*L - 3.3077115392580616*M + 0.2309699031821044*S
-	gLin := -1.2684379732850317*L + 2.6097573492876887*M - 0.3413193760026573*S
-	bLin := -0.0041960761386756*L - 0.7034186179359362*M + 1.7076146940746117*S
-
-	// Gamma correction (sRGB)
-	r = srgbGamma(rLin) * RGBMax
-	g = srgbGamma(gLin) * RGBMax
-	b = srgbGamma(bLin) * RGBMax
+	r = 4.0767416360759574*L - 3.3077115392580616*M + 0.2309699031821044*S
+	g = -1.2684379732850317*L + 2.6097573492876887*M - 0.3413193760026573*S
+	b = -0.0041960761386756*L - 0.7034186179359362*M + 1.7076146940746117*S
 
-	return clamp(r, 0, RGBMax), clamp(g, 0, RGBMax), clamp(b, 0, RGBMax)
+	return r, g, b
 }
 
 // rgbToOKLCH converts RGB to OKLCH
 // r, g, b: 0-255
 // Returns l: 0-1, c: 0-0.4, h: 0-360
-// Based on formulas from culori library
 func rgbToOKLCH(r, g, b float64) (l, c, h float64) {
-	// Convert sRGB to linear RGB
-	rLin := srgbInverseGamma(r / RGBMax)
-	gLin := srgbInverseGamma(g / RGBMax)
-	bLin := srgbInverseGamma(b / RGBMax)
+	return linearToOKLCH(srgbInverseGamma(r/RGBMax), srgbInverseGamma(g/RGBMax), srgbInverseGamma(b/RGBMax))
+}
+
+// linearToOKLCH converts linear sRGB to OKLCH
+// Returns l: 0-1, c: 0-0.4 (more for wide-gamut colors), h: 0-360
+func linearToOKLCH(r, g, b float64) (l, c, h float64) {
+	l, a, bVal := linearToOKLab(r, g, b)
+	c, h = labToPolar(a, bVal)
+	return l, c, h
+}
 
+// linearToOKLab converts linear sRGB to OKLab
+// Returns l: 0-1, a, b: roughly -0.4 to 0.4
+// Based on formulas from culori library
+func linearToOKLab(r, g, b float64) (l, a, bVal float64) {
 	// Convert linear RGB to LMS (using culori formulas)
-	cbrtL := cbrt(0.412221469470763*rLin + 0.5363325372617348*gLin + 0.0514459932675022*bLin)
-	cbrtM := cbrt(0.2119034958178252*rLin + 0.6806995506452344*gLin + 0.1073969535369406*bLin)
-	cbrtS := cbrt(0.0883024591900564*rLin + 0.2817188391361215*gLin + 0.6299787016738222*bLin)
+	cbrtL := cbrt(0.412221469470763*r + 0.5363325372617348*g + 0.0514459932675022*b)
+	cbrtM := cbrt(0.2119034958178252*r + 0.6806995506452344*g + 0.1073969535369406*b)
+	cbrtS := cbrt(0.0883024591900564*r + 0.2817188391361215*g + 0.6299787016738222*b)
 
 	// Convert LMS to OKLab (using culori formulas)
 	l = 0.210454268309314*cbrtL + 0.7936177747023054*cbrtM - 0.0040720430116193*cbrtS
-	a := 1.9779985324311684*cbrtL - 2.4285922420485799*cbrtM + 0.450593709617411*cbrtS
-	bVal := 0.0259040424655478*cbrtL + 0.7827717124575296*cbrtM - 0.8086757549230774*cbrtS
+	a = 1.9779985324311684*cbrtL - 2.4285922420485799*cbrtM + 0.450593709617411*cbrtS
+	bVal = 0.0259040424655478*cbrtL + 0.7827717124575296*cbrtM - 0.8086757549230774*cbrtS
 
 	// For achromatic colors (gray), set a and b to 0
 	if r == g && g == b {
@@ -234,23 +256,49 @@ func rgbToOKLCH(r, g, b float64) (l, c, h float64) {
 		bVal = 0
 	}
 
-	// Convert OKLab to OKLCH
-	c = math.Sqrt(a*a + bVal*bVal)
-	h = math.Atan2(bVal, a) * 180 / math.Pi
-	if h < 0 {
-		h += HueMax
+	return l, a, bVal
+}
+
+// labToLinear converts CSS LAB (D50) to linear sRGB
+func labToLinear(l, a, bVal float64) (r, g, b float64) {
+	return labToLinearWhite(l, a, bVal, IlluminantD50, AdaptationBradford)
+}
+
+// labToLinearWhite converts LAB relative to the given white point to linear sRGB
+// The D65 sRGB white is adapted to the LAB white with the given transform
+func labToLinearWhite(l, a, bVal float64, white Illuminant, method ChromaticAdaptation) (r, g, b float64) {
+	x, y, z := labToXYZ(l, a, bVal, illuminantWhites[white])
+	x, y, z = adaptXYZ(x, y, z, white, IlluminantD65, method)
+	return xyzToLinear(x, y, z)
+}
+
+// linearToLAB converts linear sRGB to CSS LAB (D50)
+func linearToLAB(r, g, b float64) (l, a, bVal float64) {
+	return linearToLABWhite(r, g, b, IlluminantD50, AdaptationBradford)
+}
+
+// linearToLABWhite converts linear sRGB to LAB relative to the given white point
+// Based on culori implementation with achromatic color fix
+func linearToLABWhite(r, g, b float64, white Illuminant, method ChromaticAdaptation) (l, a, bVal float64) {
+	x, y, z := linearToXYZ(r, g, b)
+	x, y, z = adaptXYZ(x, y, z, IlluminantD65, white, method)
+	l, a, bVal = xyzToLab(x, y, z, illuminantWhites[white])
+
+	// Fixes achromatic RGB colors having a slight chroma due to floating-point errors
+	// See: https://github.com/d3/d3-color/pull/46
+	if r == g && g == b {
+		a = 0
+		bVal = 0
 	}
 
-	return l, c, h
+	return l, a, bVal
 }
 
-// labToRGB converts LAB to RGB via XYZ
-// Using updated XYZ -> RGB matrix from CSS Color Module / culori
-func labToRGB(lVal, a, bVal float64) (r, g, b float64) {
-	// LAB to XYZ
-	y := (lVal + 16) / 116
-	x := y + a/500
-	z := y - bVal/200
+// labToXYZ converts LAB to XYZ relative to a reference white
+func labToXYZ(l, a, bVal float64, white [3]float64) (x, y, z float64) {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - bVal/200
 
 	// Inverse labF function
 	fInv := func(f float64) float64 {
@@ -261,84 +309,84 @@ func labToRGB(lVal, a, bVal float64) (r, g, b float64) {
 		return (116*f - 16) / labK
 	}
 
-	x = xyzD65[0] * fInv(x)
-	y = xyzD65[1] * fInv(y)
-	z = xyzD65[2] * fInv(z)
-
-	// XYZ to RGB (using updated CSS Color Module matrix)
-	rLin := 3.240969941904521*x - 1.537383177570093*y - 0.498610760293*z
-	gLin := -0.96924363628087*x + 1.8759675015077202*y + 0.041555057407175*z
-	bLin := 0.055630079696993*x - 0.20397695888897*y + 1.0569715142428786*z
-
-	r = srgbGamma(rLin) * RGBMax
-	g = srgbGamma(gLin) * RGBMax
-	b = srgbGamma(bLin) * RGBMax
-
-	return clamp(r, 0, RGBMax), clamp(g, 0, RGBMax), clamp(b, 0, RGBMax)
+	return white[0] * fInv(fx), white[1] * fInv(fy), white[2] * fInv(fz)
 }
 
-// rgbToLAB converts RGB to LAB via XYZ
-// Based on culori implementation with achromatic color fix
-func rgbToLAB(r, g, b float64) (l, a, bVal float64) {
-	// sRGB to linear RGB
-	rLin := srgbInverseGamma(r / RGBMax)
-	gLin := srgbInverseGamma(g / RGBMax)
-	bLin := srgbInverseGamma(b / RGBMax)
-
-	// Linear RGB to XYZ (using updated CSS Color Module matrix)
-	x := 0.41239079926595934*rLin + 0.357584339383878*gLin + 0.1804807884018343*bLin
-	y := 0.21263900587151027*rLin + 0.715168678767756*gLin + 0.07219231536073371*bLin
-	z := 0.019330818715591841*rLin + 0.11919477979462587*gLin + 0.9505321522496607*bLin
-
-	// XYZ to LAB
-	xNorm := x / xyzD65[0]
-	yNorm := y / xyzD65[1]
-	zNorm := z / xyzD65[2]
-
-	fx := labF(xNorm)
-	fy := labF(yNorm)
-	fz := labF(zNorm)
+// xyzToLab converts XYZ to LAB relative to a reference white
+func xyzToLab(x, y, z float64, white [3]float64) (l, a, bVal float64) {
+	fx := labF(x / white[0])
+	fy := labF(y / white[1])
+	fz := labF(z / white[2])
 
 	l = 116*fy - 16
 	a = 500 * (fx - fy)
 	bVal = 200 * (fy - fz)
 
-	// Fixes achromatic RGB colors having a slight chroma due to floating-point errors
-	// See: https://github.com/d3/d3-color/pull/46
-	if r == g && g == b {
-		a = 0
-		bVal = 0
-	}
-
 	return l, a, bVal
 }
 
-// xyzToRGB converts XYZ to RGB
-// Using inverse sRGB transformation matrix from CSS Color Module / culori
-func xyzToRGB(x, y, z float64) (r, g, b float64) {
-	rLin := 3.240969941904521*x - 1.537383177570093*y - 0.498610760293*z
-	gLin := -0.96924363628087*x + 1.8759675015077202*y + 0.041555057407175*z
-	bLin := 0.055630079696993*x - 0.20397695888897*y + 1.0569715142428786*z
+// lchToLinear converts CSS LCH (D50) to linear sRGB via LAB
+// l: 0-100, c: 0-150, h: 0-360
+func lchToLinear(l, c, h float64) (r, g, b float64) {
+	hRad := h * math.Pi / 180
+	return labToLinear(l, c*math.Cos(hRad), c*math.Sin(hRad))
+}
 
-	r = srgbGamma(rLin) * RGBMax
-	g = srgbGamma(gLin) * RGBMax
-	b = srgbGamma(bLin) * RGBMax
+// linearToLCH converts linear sRGB to CSS LCH (D50) via LAB
+// Returns l: 0-100, c: 0-150, h: 0-360
+func linearToLCH(r, g, b float64) (l, c, h float64) {
+	return linearToLCHWhite(r, g, b, IlluminantD50, AdaptationBradford)
+}
 
-	return clamp(r, 0, RGBMax), clamp(g, 0, RGBMax), clamp(b, 0, RGBMax)
+// linearToLCHWhite converts linear sRGB to LCH relative to the given white point
+// Returns l: 0-100, c: 0-150, h: 0-360
+func linearToLCHWhite(r, g, b float64, white Illuminant, method ChromaticAdaptation) (l, c, h float64) {
+	l, a, bVal := linearToLABWhite(r, g, b, white, method)
+	c, h = labToPolar(a, bVal)
+	return l, c, h
+}
+
+// xyzToLinear converts D65 XYZ to linear sRGB
+// Using inverse sRGB transformation matrix from CSS Color Module / culori
+func xyzToLinear(x, y, z float64) (r, g, b float64) {
+	return mulMatrix3(xyzToSRGBMatrix, x, y, z)
 }
 
-// rgbToXYZ converts RGB to XYZ
+// linearToXYZ converts linear sRGB to D65 XYZ
 // Using sRGB transformation matrix from CSS Color Module / culori
-func rgbToXYZ(r, g, b float64) (x, y, z float64) {
-	rLin := srgbInverseGamma(r / RGBMax)
-	gLin := srgbInverseGamma(g / RGBMax)
-	bLin := srgbInverseGamma(b / RGBMax)
+func linearToXYZ(r, g, b float64) (x, y, z float64) {
+	return mulMatrix3(srgbToXYZMatrix, r, g, b)
+}
 
-	x = 0.41239079926595934*rLin + 0.357584339383878*gLin + 0.1804807884018343*bLin
-	y = 0.21263900587151027*rLin + 0.715168678767756*gLin + 0.07219231536073371*bLin
-	z = 0.019330818715591841*rLin + 0.11919477979462587*gLin + 0.9505321522496607*bLin
+// linearToRGB gamma-encodes linear sRGB
+// Returns RGB values clamped to the 0-255 range. Values within 1e-9 of an
+// integer are snapped to it, so 8-bit colors come back exactly after a round
+// trip through any other space instead of as e.g. 254.99999999999986.
+func linearToRGB(rLin, gLin, bLin float64) (r, g, b float64) {
+	r = snapToInteger(srgbGamma(rLin) * RGBMax)
+	g = snapToInteger(srgbGamma(gLin) * RGBMax)
+	b = snapToInteger(srgbGamma(bLin) * RGBMax)
 
-	return x, y, z
+	return clamp(r, 0, RGBMax), clamp(g, 0, RGBMax), clamp(b, 0, RGBMax)
+}
+
+// snapToInteger removes floating-point noise from values that are integers
+// after a round trip through another color space (e.g. 254.99999999999986)
+func snapToInteger(v float64) float64 {
+	if r := math.Round(v); math.Abs(v-r) < 1e-9 {
+		return r
+	}
+	return v
+}
+
+// labToPolar converts Cartesian a/b to chroma and hue (0-360)
+func labToPolar(a, b float64) (c, h float64) {
+	c = math.Sqrt(a*a + b*b)
+	h = math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += HueMax
+	}
+	return c, h
 }
 
 // hwbToRGB converts HWB to RGB
@@ -348,6 +396,12 @@ func hwbToRGB(h, w, bVal float64) (r, g, b float64) {
 	w /= LightnessMax
 	bVal /= LightnessMax
 
+	// Whiteness and blackness adding up to 100% or more give a gray (CSS Color 4 §8.2)
+	if w+bVal >= 1 {
+		gray := w / (w + bVal) * RGBMax
+		return gray, gray, gray
+	}
+
 	// First get RGB from H
 	r, g, b = hslToRGB(h, SaturationMax, LightnessMax/2) // Use full saturation/lightness
 
@@ -434,12 +488,185 @@ func labF(t float64) float64 {
 }
 
 func cbrt(x float64) float64 {
-	return math.Pow(x, 1.0/3.0)
+	return math.Cbrt(x) // defined for negative values of out-of-gamut colors
+}
+
+// Predefined RGB color spaces for the CSS color() function
+// Matrices convert linear-light RGB to/from XYZ relative to the space's own
+// white point (from CSS Color Module Level 4 sample code)
+
+// predefinedSpace describes a color() space by its primaries and transfer function
+type predefinedSpace struct {
+	toXYZ      [3][3]float64
+	fromXYZ    [3][3]float64
+	toLinear   func(float64) float64
+	fromLinear func(float64) float64
+	d50        bool // XYZ values are relative to D50 rather than D65
+}
+
+var identityMatrix = [3][3]float64{
+	{1, 0, 0},
+	{0, 1, 0},
+	{0, 0, 1},
+}
+
+var srgbToXYZMatrix = [3][3]float64{
+	{0.41239079926595934, 0.357584339383878, 0.1804807884018343},
+	{0.21263900587151027, 0.715168678767756, 0.07219231536073371},
+	{0.019330818715591841, 0.11919477979462587, 0.9505321522496607},
+}
+
+var xyzToSRGBMatrix = [3][3]float64{
+	{3.240969941904521, -1.537383177570093, -0.498610760293},
+	{-0.96924363628087, 1.8759675015077202, 0.041555057407175},
+	{0.055630079696993, -0.20397695888897, 1.0569715142428786},
+}
+
+var displayP3ToXYZMatrix = [3][3]float64{
+	{0.4865709486482162, 0.26566769316909306, 0.1982172852343625},
+	{0.2289745640697488, 0.6917385218365064, 0.079286914093745},
+	{0.0, 0.04511338185890264, 1.043944368900976},
+}
+
+var xyzToDisplayP3Matrix = [3][3]float64{
+	{2.493496911941425, -0.9313836179191239, -0.40271078445071684},
+	{-0.8294889695615747, 1.7626640603183463, 0.023624685841943577},
+	{0.03584583024378447, -0.07617238926804182, 0.9568845240076872},
+}
+
+var rec2020ToXYZMatrix = [3][3]float64{
+	{0.6369580483012914, 0.14461690358620832, 0.1688809751641721},
+	{0.2627002120112671, 0.6779980715188708, 0.05930171646986196},
+	{0.0, 0.028072693049087428, 1.060985057710791},
+}
+
+var xyzToRec2020Matrix = [3][3]float64{
+	{1.716651187971268, -0.355670783776392, -0.253366281373660},
+	{-0.666684351832489, 1.616481236634939, 0.0157685458139111},
+	{0.017639857445311, -0.042770613257809, 0.942103121235474},
+}
+
+var a98RGBToXYZMatrix = [3][3]float64{
+	{0.5766690429101305, 0.1855582379065463, 0.1882286462349947},
+	{0.29734497525053605, 0.6273635662554661, 0.07529145849399788},
+	{0.02703136138641234, 0.07068885253582723, 0.9913375368376388},
+}
+
+var xyzToA98RGBMatrix = [3][3]float64{
+	{2.0415879038107465, -0.5650069742788596, -0.34473135077832956},
+	{-0.9692436362808795, 1.8759675015077202, 0.04155505740717557},
+	{0.013444280632031142, -0.11836239223101838, 1.0151749943912054},
+}
+
+// ProPhoto RGB is defined relative to D50
+var proPhotoToXYZMatrix = [3][3]float64{
+	{0.7977666449006423, 0.13518129740053308, 0.0313477341283922},
+	{0.2880748288194013, 0.711835234241873, 0.00008993693872564},
+	{0.0, 0.0, 0.8251046025104602},
+}
+
+var xyzToProPhotoMatrix = [3][3]float64{
+	{1.3457868816471583, -0.25557208737979464, -0.05110186497554526},
+	{-0.5446307051249019, 1.5082477428451468, 0.02052744743642139},
+	{0.0, 0.0, 1.2119675456389452},
+}
+
+var predefinedSpaces = map[ColorFormat]predefinedSpace{
+	FormatSRGB:        {srgbToXYZMatrix, xyzToSRGBMatrix, srgbToLinear, srgbFromLinear, false},
+	FormatSRGBLinear:  {srgbToXYZMatrix, xyzToSRGBMatrix, linearTransfer, linearTransfer, false},
+	FormatDisplayP3:   {displayP3ToXYZMatrix, xyzToDisplayP3Matrix, srgbToLinear, srgbFromLinear, false},
+	FormatRec2020:     {rec2020ToXYZMatrix, xyzToRec2020Matrix, rec2020ToLinear, rec2020FromLinear, false},
+	FormatA98RGB:      {a98RGBToXYZMatrix, xyzToA98RGBMatrix, a98RGBToLinear, a98RGBFromLinear, false},
+	FormatProPhotoRGB: {proPhotoToXYZMatrix, xyzToProPhotoMatrix, proPhotoToLinear, proPhotoFromLinear, true},
+	FormatXYZD50:      {identityMatrix, identityMatrix, linearTransfer, linearTransfer, true},
+	FormatXYZD65:      {identityMatrix, identityMatrix, linearTransfer, linearTransfer, false},
+}
+
+// predefinedToXYZ converts color() channel values to D65 XYZ
+func predefinedToXYZ(space predefinedSpace, c1, c2, c3 float64) (x, y, z float64) {
+	x, y, z = mulMatrix3(space.toXYZ, space.toLinear(c1), space.toLinear(c2), space.toLinear(c3))
+	if space.d50 {
+		x, y, z = adaptXYZ(x, y, z, IlluminantD50, IlluminantD65, AdaptationBradford)
+	}
+	return x, y, z
+}
+
+// xyzToPredefined converts D65 XYZ to color() channel values
+func xyzToPredefined(space predefinedSpace, x, y, z float64) (c1, c2, c3 float64) {
+	if space.d50 {
+		x, y, z = adaptXYZ(x, y, z, IlluminantD65, IlluminantD50, AdaptationBradford)
+	}
+	c1, c2, c3 = mulMatrix3(space.fromXYZ, x, y, z)
+	return space.fromLinear(c1), space.fromLinear(c2), space.fromLinear(c3)
+}
+
+// predefinedToLinear converts color() channel values to linear sRGB (unclamped)
+func predefinedToLinear(space predefinedSpace, c1, c2, c3 float64) (r, g, b float64) {
+	return xyzToLinear(predefinedToXYZ(space, c1, c2, c3))
+}
+
+// linearToPredefined converts linear sRGB to color() channel values
+func linearToPredefined(space predefinedSpace, r, g, b float64) (c1, c2, c3 float64) {
+	x, y, z := linearToXYZ(r, g, b)
+	return xyzToPredefined(space, x, y, z)
+}
+
+// Transfer functions for predefined spaces
+// All of them extend to negative values by mirroring, as in CSS Color 4
+
+func linearTransfer(v float64) float64 { return v }
+
+func srgbToLinear(v float64) float64 {
+	return math.Copysign(srgbInverseGamma(math.Abs(v)), v)
+}
+
+func srgbFromLinear(v float64) float64 {
+	return math.Copysign(srgbGamma(math.Abs(v)), v)
+}
+
+func rec2020ToLinear(v float64) float64 {
+	abs := math.Abs(v)
+	if abs < rec2020Beta*rec2020LinearFactor {
+		return v / rec2020LinearFactor
+	}
+	return math.Copysign(math.Pow((abs+rec2020Alpha-1)/rec2020Alpha, 1/rec2020Power), v)
+}
+
+func rec2020FromLinear(v float64) float64 {
+	abs := math.Abs(v)
+	if abs < rec2020Beta {
+		return v * rec2020LinearFactor
+	}
+	return math.Copysign(rec2020Alpha*math.Pow(abs, rec2020Power)-(rec2020Alpha-1), v)
+}
+
+func a98RGBToLinear(v float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), a98RGBGamma), v)
+}
+
+func a98RGBFromLinear(v float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), 1/a98RGBGamma), v)
+}
+
+func proPhotoToLinear(v float64) float64 {
+	abs := math.Abs(v)
+	if abs <= proPhotoLinearThreshold*proPhotoLinearFactor {
+		return v / proPhotoLinearFactor
+	}
+	return math.Copysign(math.Pow(abs, proPhotoGamma), v)
+}
+
+func proPhotoFromLinear(v float64) float64 {
+	abs := math.Abs(v)
+	if abs >= proPhotoLinearThreshold {
+		return math.Copysign(math.Pow(abs, 1/proPhotoGamma), v)
+	}
+	return v * proPhotoLinearFactor
 }
 
-// D65 illuminant XYZ values (from CSS Color Module / culori)
-var xyzD65 = [3]float64{
-	0.3127 / 0.329, // ≈ 0.9504559270516716
-	1.0,
-	(1 - 0.3127 - 0.329) / 0.329, // ≈ 1.08905775075988
+// mulMatrix3 multiplies a 3x3 matrix by a column vector
+func mulMatrix3(m [3][3]float64, v1, v2, v3 float64) (float64, float64, float64) {
+	return m[0][0]*v1 + m[0][1]*v2 + m[0][2]*v3,
+		m[1][0]*v1 + m[1][1]*v2 + m[1][2]*v3,
+		m[2][0]*v1 + m[2][1]*v2 + m[2][2]*v3
 }
@@ -7,52 +7,119 @@ import (
 	"strings"
 )
 
+// ConvertOptions controls how a converted color is serialized
+type ConvertOptions struct {
+	PreserveAlpha bool                // keep the alpha channel of the input
+	ModernSyntax  bool                // emit CSS Color 4 space-separated rgb()/hsl() instead of the legacy comma form
+	NamedFallback bool                // for the named target, fall back to the nearest keyword by OKLCH ΔE
+	GamutMapping  GamutMapping        // how wide-gamut input is brought into sRGB for sRGB-based targets (default: clip)
+	WhitePoint    Illuminant          // reference white for lab/lch output (default: D50)
+	Adaptation    ChromaticAdaptation // chromatic adaptation transform to the reference white (default: bradford)
+}
+
 // Convert converts a color from one format to another
 // color: input color string
-// targetFormat: target format (hex, rgb, hsl, hsla, hsb, oklch, lab, xyz, hwb, cmyk)
+// targetFormat: target format (hex, rgb, hsl, hsla, hsb, oklch, oklab, lab, lch, hct, xyz, hwb, cmyk, named, or a color() space)
 // preserveAlpha: whether to preserve alpha channel
 func Convert(color string, targetFormat string, preserveAlpha bool) (string, error) {
+	return ConvertWithOptions(color, targetFormat, ConvertOptions{PreserveAlpha: preserveAlpha})
+}
+
+// ConvertWithOptions converts a color from one format to another using the given options
+func ConvertWithOptions(color string, targetFormat string, opts ConvertOptions) (string, error) {
 	// Detect input format
 	data, err := DetectFormat(color)
 	if err != nil {
 		return "", fmt.Errorf("failed to detect color format: %w", err)
 	}
 
+	return FormatColor(data, targetFormat, opts)
+}
+
+// FormatColor serializes parsed color data in the target format using the given options
+func FormatColor(data ColorData, targetFormat string, opts ConvertOptions) (string, error) {
 	// Parse target format
 	format := ColorFormat(strings.ToLower(targetFormat))
 	if !isValidFormat(format) {
-		return "", fmt.Errorf("invalid target format: %s (supported: hex, rgb, hsl, hsla, hsb, oklch, lab, xyz, hwb, cmyk)", targetFormat)
+		return "", fmt.Errorf("invalid target format: %s (supported: %s)", targetFormat, strings.Join(GetSupportedFormats(), ", "))
+	}
+
+	var err error
+	opts.GamutMapping, err = ParseGamutMapping(string(opts.GamutMapping))
+	if err != nil {
+		return "", err
+	}
+
+	opts.WhitePoint, err = ParseIlluminant(string(opts.WhitePoint))
+	if err != nil {
+		return "", err
+	}
+
+	opts.Adaptation, err = ParseChromaticAdaptation(string(opts.Adaptation))
+	if err != nil {
+		return "", err
 	}
 
-	// Get RGB values
-	r, g, b := data.Color.R, data.Color.G, data.Color.B
+	return formatColorData(data, format, opts)
+}
+
+// formatColorData serializes parsed color data in the target format
+// Wide color spaces are written from the unclamped linear value, so converting
+// between them is lossless; sRGB-based formats use the clamped sRGB value
+func formatColorData(data ColorData, format ColorFormat, opts ConvertOptions) (string, error) {
+	// Get RGB values, gamut-mapping wide-gamut input unless plain clipping was requested
+	color := data.Color
+	if opts.GamutMapping != GamutMappingClip && !inGamut(data.Linear, predefinedSpaces[FormatSRGB]) {
+		color = mapToGamut(data.Linear, predefinedSpaces[FormatSRGB], opts.GamutMapping).SRGB()
+	}
+	r, g, b := color.R, color.G, color.B
 	a := data.Color.A
 
 	// Handle alpha preservation
-	if !preserveAlpha {
+	if !opts.PreserveAlpha {
 		a = 1.0
 	}
 
+	linear := data.Linear
+	linear.A = a
+
 	// Convert to target format
 	switch format {
 	case FormatHEX:
 		return formatHEX(r, g, b, a), nil
 	case FormatRGB, FormatRGBA:
+		if opts.ModernSyntax {
+			return formatRGBModern(r, g, b, a, format == FormatRGBA), nil
+		}
 		return formatRGB(r, g, b, a, format == FormatRGBA), nil
 	case FormatHSL, FormatHSLA:
+		if opts.ModernSyntax {
+			return formatHSLModern(r, g, b, a, format == FormatHSLA), nil
+		}
 		return formatHSL(r, g, b, a, format == FormatHSLA), nil
 	case FormatHSB, FormatHSV:
 		return formatHSB(r, g, b, a), nil
 	case FormatOKLCH:
-		return formatOKLCH(r, g, b, a), nil
+		return formatOKLCH(linear), nil
+	case FormatOKLab:
+		return formatOKLab(linear), nil
 	case FormatLAB:
-		return formatLAB(r, g, b, a), nil
+		return formatLAB(linear, opts.WhitePoint, opts.Adaptation), nil
+	case FormatLCH:
+		return formatLCH(linear, opts.WhitePoint, opts.Adaptation), nil
+	case FormatHCT:
+		return formatHCT(linear), nil
 	case FormatXYZ:
-		return formatXYZ(r, g, b, a), nil
+		return formatXYZ(linear), nil
 	case FormatHWB:
 		return formatHWB(r, g, b, a), nil
 	case FormatCMYK:
 		return formatCMYK(r, g, b, a), nil
+	case FormatNamed:
+		return formatNamed(r, g, b, a, opts.NamedFallback)
+	case FormatSRGB, FormatSRGBLinear, FormatDisplayP3, FormatRec2020,
+		FormatA98RGB, FormatProPhotoRGB, FormatXYZD50, FormatXYZD65:
+		return formatPredefined(format, linear), nil
 	default:
 		return "", fmt.Errorf("unsupported target format: %s", format)
 	}
@@ -63,7 +130,9 @@ func isValidFormat(format ColorFormat) bool {
 	switch format {
 	case FormatHEX, FormatRGB, FormatRGBA, FormatHSL, FormatHSLA,
 		FormatHSB, FormatHSV, FormatOKLCH, FormatLAB, FormatXYZ,
-		FormatHWB, FormatCMYK:
+		FormatHWB, FormatCMYK, FormatNamed, FormatOKLab, FormatLCH, FormatHCT,
+		FormatSRGB, FormatSRGBLinear, FormatDisplayP3, FormatRec2020,
+		FormatA98RGB, FormatProPhotoRGB, FormatXYZD50, FormatXYZD65:
 		return true
 	default:
 		return false
@@ -95,6 +164,19 @@ func formatRGB(r, g, b, a float64, includeAlpha bool) string {
 	return fmt.Sprintf("rgb(%s, %s, %s)", rStr, gStr, bStr)
 }
 
+// formatRGBModern formats RGB values as space-separated rgb() (CSS Color 4)
+// Alpha is included when requested or when the color is translucent
+func formatRGBModern(r, g, b, a float64, includeAlpha bool) string {
+	rStr := strconv.FormatFloat(r, 'f', -1, 64)
+	gStr := strconv.FormatFloat(g, 'f', -1, 64)
+	bStr := strconv.FormatFloat(b, 'f', -1, 64)
+
+	if includeAlpha || a < 1.0 {
+		return fmt.Sprintf("rgb(%s %s %s / %.2f)", rStr, gStr, bStr, a)
+	}
+	return fmt.Sprintf("rgb(%s %s %s)", rStr, gStr, bStr)
+}
+
 // formatHSL formats RGB values as HSL
 func formatHSL(r, g, b, a float64, includeAlpha bool) string {
 	h, s, l := rgbToHSL(r, g, b)
@@ -109,6 +191,21 @@ func formatHSL(r, g, b, a float64, includeAlpha bool) string {
 	return fmt.Sprintf("hsl(%s, %s%%, %s%%)", hStr, sStr, lStr)
 }
 
+// formatHSLModern formats RGB values as space-separated hsl() (CSS Color 4)
+// Alpha is included when requested or when the color is translucent
+func formatHSLModern(r, g, b, a float64, includeAlpha bool) string {
+	h, s, l := rgbToHSL(r, g, b)
+
+	hStr := strconv.FormatFloat(h, 'f', -1, 64)
+	sStr := strconv.FormatFloat(s, 'f', -1, 64)
+	lStr := strconv.FormatFloat(l, 'f', -1, 64)
+
+	if includeAlpha || a < 1.0 {
+		return fmt.Sprintf("hsl(%s %s%% %s%% / %.2f)", hStr, sStr, lStr, a)
+	}
+	return fmt.Sprintf("hsl(%s %s%% %s%%)", hStr, sStr, lStr)
+}
+
 // formatHSB formats RGB values as HSB
 func formatHSB(r, g, b, a float64) string {
 	h, s, v := rgbToHSB(r, g, b)
@@ -120,44 +217,86 @@ func formatHSB(r, g, b, a float64) string {
 	return fmt.Sprintf("hsb(%s, %s%%, %s%%)", hStr, sStr, vStr)
 }
 
-// formatOKLCH formats RGB values as OKLCH
-func formatOKLCH(r, g, b, a float64) string {
-	l, c, h := rgbToOKLCH(r, g, b)
+// formatOKLCH formats a linear color as OKLCH
+func formatOKLCH(lc LinearColor) string {
+	l, c, h := linearToOKLCH(lc.R, lc.G, lc.B)
 
 	lStr := strconv.FormatFloat(l, 'f', 4, 64)
 	cStr := strconv.FormatFloat(c, 'f', 4, 64)
 	hStr := strconv.FormatFloat(h, 'f', 2, 64)
 
-	if a < 1.0 {
-		return fmt.Sprintf("oklch(%s %s %s / %.2f)", lStr, cStr, hStr, a)
+	if lc.A < 1.0 {
+		return fmt.Sprintf("oklch(%s %s %s / %.2f)", lStr, cStr, hStr, lc.A)
 	}
 	return fmt.Sprintf("oklch(%s %s %s)", lStr, cStr, hStr)
 }
 
-// formatLAB formats RGB values as LAB
-func formatLAB(r, g, b, a float64) string {
-	l, aVal, bVal := rgbToLAB(r, g, b)
+// formatLAB formats a linear color as LAB relative to the given white point
+func formatLAB(lc LinearColor, white Illuminant, method ChromaticAdaptation) string {
+	l, aVal, bVal := linearToLABWhite(lc.R, lc.G, lc.B, white, method)
 
 	lStr := strconv.FormatFloat(l, 'f', 2, 64)
 	aStr := strconv.FormatFloat(aVal, 'f', 2, 64)
 	bStr := strconv.FormatFloat(bVal, 'f', 2, 64)
 
-	if a < 1.0 {
-		return fmt.Sprintf("lab(%s %s %s / %.2f)", lStr, aStr, bStr, a)
+	if lc.A < 1.0 {
+		return fmt.Sprintf("lab(%s %s %s / %.2f)", lStr, aStr, bStr, lc.A)
 	}
 	return fmt.Sprintf("lab(%s %s %s)", lStr, aStr, bStr)
 }
 
-// formatXYZ formats RGB values as XYZ
-func formatXYZ(r, g, b, a float64) string {
-	x, y, z := rgbToXYZ(r, g, b)
+// formatOKLab formats a linear color as OKLab
+func formatOKLab(lc LinearColor) string {
+	l, aVal, bVal := linearToOKLab(lc.R, lc.G, lc.B)
+
+	lStr := strconv.FormatFloat(l, 'f', 4, 64)
+	aStr := strconv.FormatFloat(aVal, 'f', 4, 64)
+	bStr := strconv.FormatFloat(bVal, 'f', 4, 64)
+
+	if lc.A < 1.0 {
+		return fmt.Sprintf("oklab(%s %s %s / %.2f)", lStr, aStr, bStr, lc.A)
+	}
+	return fmt.Sprintf("oklab(%s %s %s)", lStr, aStr, bStr)
+}
+
+// formatLCH formats a linear color as CIE LCH relative to the given white point
+func formatLCH(lc LinearColor, white Illuminant, method ChromaticAdaptation) string {
+	l, c, h := linearToLCHWhite(lc.R, lc.G, lc.B, white, method)
+
+	lStr := strconv.FormatFloat(l, 'f', 2, 64)
+	cStr := strconv.FormatFloat(c, 'f', 2, 64)
+	hStr := strconv.FormatFloat(h, 'f', 2, 64)
+
+	if lc.A < 1.0 {
+		return fmt.Sprintf("lch(%s %s %s / %.2f)", lStr, cStr, hStr, lc.A)
+	}
+	return fmt.Sprintf("lch(%s %s %s)", lStr, cStr, hStr)
+}
+
+// formatHCT formats a linear color as Material HCT
+func formatHCT(lc LinearColor) string {
+	h, c, t := linearToHCT(lc.R, lc.G, lc.B)
+
+	hStr := strconv.FormatFloat(h, 'f', 2, 64)
+	cStr := strconv.FormatFloat(c, 'f', 2, 64)
+	tStr := strconv.FormatFloat(t, 'f', 2, 64)
+
+	if lc.A < 1.0 {
+		return fmt.Sprintf("hct(%s %s %s / %.2f)", hStr, cStr, tStr, lc.A)
+	}
+	return fmt.Sprintf("hct(%s %s %s)", hStr, cStr, tStr)
+}
+
+// formatXYZ formats a linear color as XYZ
+func formatXYZ(lc LinearColor) string {
+	x, y, z := linearToXYZ(lc.R, lc.G, lc.B)
 
 	xStr := strconv.FormatFloat(x, 'f', 4, 64)
 	yStr := strconv.FormatFloat(y, 'f', 4, 64)
 	zStr := strconv.FormatFloat(z, 'f', 4, 64)
 
-	if a < 1.0 {
-		return fmt.Sprintf("xyz(%s %s %s / %.2f)", xStr, yStr, zStr, a)
+	if lc.A < 1.0 {
+		return fmt.Sprintf("xyz(%s %s %s / %.2f)", xStr, yStr, zStr, lc.A)
 	}
 	return fmt.Sprintf("xyz(%s %s %s)", xStr, yStr, zStr)
 }
@@ -191,12 +330,28 @@ func formatCMYK(r, g, b, a float64) string {
 	return fmt.Sprintf("cmyk(%s%% %s%% %s%% %s%%)", cStr, mStr, yStr, kStr)
 }
 
+// formatPredefined formats a linear color as CSS color() in a predefined color space
+func formatPredefined(format ColorFormat, lc LinearColor) string {
+	c1, c2, c3 := linearToPredefined(predefinedSpaces[format], lc.R, lc.G, lc.B)
+
+	c1Str := strconv.FormatFloat(c1, 'f', 4, 64)
+	c2Str := strconv.FormatFloat(c2, 'f', 4, 64)
+	c3Str := strconv.FormatFloat(c3, 'f', 4, 64)
+
+	if lc.A < 1.0 {
+		return fmt.Sprintf("color(%s %s %s %s / %.2f)", format, c1Str, c2Str, c3Str, lc.A)
+	}
+	return fmt.Sprintf("color(%s %s %s %s)", format, c1Str, c2Str, c3Str)
+}
+
 // GetSupportedFormats returns a list of supported color formats
 func GetSupportedFormats() []string {
 	return []string{
 		"hex", "rgb", "rgba", "hsl", "hsla",
-		"hsb", "hsv", "oklch", "lab", "xyz",
-		"hwb", "cmyk",
+		"hsb", "hsv", "oklch", "oklab", "lab", "lch", "hct", "xyz",
+		"hwb", "cmyk", "named",
+		"srgb", "srgb-linear", "display-p3", "rec2020",
+		"a98-rgb", "prophoto-rgb", "xyz-d50", "xyz-d65",
 	}
 }
 
@@ -328,6 +328,50 @@ func TestAlphaPreservation(t *testing.T) {
 	}
 }
 
+// TestAlphaSyntaxEverywhere tests that every format accepts its alpha as a
+// number or a percentage
+func TestAlphaSyntaxEverywhere(t *testing.T) {
+	inputs := []string{
+		"rgb(255 0 0 / 50%)",
+		"rgba(255, 0, 0, 50%)",
+		"hsl(0 100% 50% / 50%)",
+		"hsla(0, 100%, 50%, 50%)",
+		"hsb(0, 100%, 100%, 50%)",
+		"hsv(0, 100%, 100%, 0.5)",
+		"hwb(0 0% 0% / 50%)",
+		"oklch(0.5 0.1 30 / 50%)",
+		"oklab(0.5 0.1 0.1 / 50%)",
+		"lab(50 20 30 / 50%)",
+		"lch(50 30 60 / 50%)",
+		"xyz(0.2 0.3 0.4 / 50%)",
+		"hct(30 40 50 / 50%)",
+		"cmyk(0% 100% 100% 0% / 50%)",
+		"color(display-p3 1 0 0 / 50%)",
+		"lab(50 20 30 / 0.5)",
+	}
+	for _, input := range inputs {
+		data, err := DetectFormat(input)
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+		if data.Color.A != 0.5 || data.Linear.A != 0.5 {
+			t.Errorf("%s: expected alpha 0.5, got %v (linear %v)", input, data.Color.A, data.Linear.A)
+		}
+	}
+
+	// Alpha is clamped to 0-1 in every format
+	for _, input := range []string{"lab(50 20 30 / 150%)", "xyz(0.2 0.3 0.4 / 2)"} {
+		data, err := DetectFormat(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.Linear.A != AlphaMax {
+			t.Errorf("%s: expected alpha clamped to 1, got %v", input, data.Linear.A)
+		}
+	}
+}
+
 // TestBoundaryValues tests conversion with boundary color values
 func TestBoundaryValues(t *testing.T) {
 	tests := []struct {
@@ -665,3 +709,378 @@ func TestRGBPercentageNotation(t *testing.T) {
 		})
 	}
 }
+
+// TestCSSColor4Syntax tests space-separated rgb()/hsl() with slash alpha
+func TestCSSColor4Syntax(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectFormat ColorFormat
+		expectR      float64
+		expectG      float64
+		expectB      float64
+		expectAlpha  float64
+	}{
+		{"RGB modern", "rgb(255 0 0)", FormatRGB, 255, 0, 0, 1},
+		{"RGB modern percent alpha", "rgb(255 0 0 / 50%)", FormatRGBA, 255, 0, 0, 0.5},
+		{"RGB modern number alpha", "rgb(0 128 255 / 0.25)", FormatRGBA, 0, 128, 255, 0.25},
+		{"RGBA alias modern", "rgba(100% 0% 0% / .5)", FormatRGBA, 255, 0, 0, 0.5},
+		{"RGB legacy percent alpha", "rgba(255, 0, 0, 50%)", FormatRGBA, 255, 0, 0, 0.5},
+		{"HSL modern deg", "hsl(120deg 100% 50% / .3)", FormatHSLA, 0, 255, 0, 0.3},
+		{"HSL modern", "hsl(240 100% 50%)", FormatHSL, 0, 0, 255, 1},
+		{"HSL modern percent alpha", "hsl(0 100% 50% / 40%)", FormatHSLA, 255, 0, 0, 0.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DetectFormat(tt.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.input, err)
+			}
+			if data.Format != tt.expectFormat {
+				t.Errorf("Expected format %s, got %s", tt.expectFormat, data.Format)
+			}
+			if !almostEqual(data.Color.R, tt.expectR, 0.5) ||
+				!almostEqual(data.Color.G, tt.expectG, 0.5) ||
+				!almostEqual(data.Color.B, tt.expectB, 0.5) {
+				t.Errorf("Expected RGB(%f, %f, %f), got RGB(%f, %f, %f)",
+					tt.expectR, tt.expectG, tt.expectB,
+					data.Color.R, data.Color.G, data.Color.B)
+			}
+			if !almostEqual(data.Color.A, tt.expectAlpha, 0.01) {
+				t.Errorf("Expected A=%f, got %f", tt.expectAlpha, data.Color.A)
+			}
+		})
+	}
+}
+
+// TestConvertModernSyntax tests emitting CSS Color 4 space-separated syntax
+func TestConvertModernSyntax(t *testing.T) {
+	tests := []struct {
+		input        string
+		targetFormat string
+		expected     string
+	}{
+		{"#FF0000", "rgb", "rgb(255 0 0)"},
+		{"#FF0000", "rgba", "rgb(255 0 0 / 1.00)"},
+		{"#FF000080", "rgb", "rgb(255 0 0 / 0.50)"},
+		{"#00FF00", "hsl", "hsl(120 100% 50%)"},
+		{"rgba(255, 0, 0, 0.5)", "hsla", "hsl(0 100% 50% / 0.50)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+"->"+tt.targetFormat, func(t *testing.T) {
+			result, err := ConvertWithOptions(tt.input, tt.targetFormat, ConvertOptions{
+				PreserveAlpha: true,
+				ModernSyntax:  true,
+			})
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestHueUnitsAndWrapping tests CSS angle units and hue wrapping in hue-bearing formats
+func TestHueUnitsAndWrapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		reference string
+	}{
+		{"HSL negative hue", "hsl(-30, 100%, 50%)", "hsl(330, 100%, 50%)"},
+		{"HSL hue above 360", "hsl(400, 100%, 50%)", "hsl(40, 100%, 50%)"},
+		{"HSL turn", "hsl(0.5turn 100% 50%)", "hsl(180, 100%, 50%)"},
+		{"HSL rad", "hsl(3.14159265rad 100% 50%)", "hsl(180, 100%, 50%)"},
+		{"HSL grad", "hsla(200grad, 100%, 50%, 1)", "hsl(180, 100%, 50%)"},
+		{"HSB negative hue", "hsb(-120, 100%, 100%)", "hsb(240, 100%, 100%)"},
+		{"HWB turn", "hwb(0.25turn 0% 0%)", "hwb(90 0% 0%)"},
+		{"OKLCH deg", "oklch(0.6 0.1 -90deg)", "oklch(0.6 0.1 270)"},
+		{"OKLCH turn", "oklch(0.6 0.1 1.25turn)", "oklch(0.6 0.1 90)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DetectFormat(tt.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.input, err)
+			}
+			ref, err := DetectFormat(tt.reference)
+			if err != nil {
+				t.Fatalf("Failed to parse reference %s: %v", tt.reference, err)
+			}
+			if !almostEqual(data.Color.R, ref.Color.R, 0.01) ||
+				!almostEqual(data.Color.G, ref.Color.G, 0.01) ||
+				!almostEqual(data.Color.B, ref.Color.B, 0.01) {
+				t.Errorf("Expected RGB(%f, %f, %f), got RGB(%f, %f, %f)",
+					ref.Color.R, ref.Color.G, ref.Color.B,
+					data.Color.R, data.Color.G, data.Color.B)
+			}
+		})
+	}
+}
+
+// TestColorFunctionConversion tests emitting CSS color() in predefined color spaces
+func TestColorFunctionConversion(t *testing.T) {
+	tests := []struct {
+		input        string
+		targetFormat string
+		expected     string
+	}{
+		{"#FF0000", "srgb", "color(srgb 1.0000 0.0000 0.0000)"},
+		{"#FF0000", "display-p3", "color(display-p3 0.9175 0.2003 0.1386)"},
+		{"#FF0000", "rec2020", "color(rec2020 0.7920 0.2310 0.0738)"},
+		{"#FF0000", "a98-rgb", "color(a98-rgb 0.8586 0.0000 0.0000)"},
+		{"#FF0000", "prophoto-rgb", "color(prophoto-rgb 0.7022 0.2757 0.1035)"},
+		{"#FF0000", "xyz-d65", "color(xyz-d65 0.4124 0.2126 0.0193)"},
+		{"#FF0000", "xyz-d50", "color(xyz-d50 0.4361 0.2225 0.0139)"},
+		{"#808080", "srgb-linear", "color(srgb-linear 0.2159 0.2159 0.2159)"},
+		{"#FF000080", "display-p3", "color(display-p3 0.9175 0.2003 0.1386 / 0.50)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+"->"+tt.targetFormat, func(t *testing.T) {
+			result, err := Convert(tt.input, tt.targetFormat, true)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestColorFunctionParsing tests parsing CSS color() in predefined color spaces
+func TestColorFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectFormat ColorFormat
+		expectR      float64
+		expectG      float64
+		expectB      float64
+		expectAlpha  float64
+	}{
+		{"color(srgb 1 0 0)", FormatSRGB, 255, 0, 0, 1},
+		{"color(srgb 100% 50% 0%)", FormatSRGB, 255, 127.5, 0, 1},
+		{"color(display-p3 0.9175 0.2003 0.1386)", FormatDisplayP3, 255, 0, 0, 1},
+		{"color(rec2020 0.7919 0.2310 0.0738 / 50%)", FormatRec2020, 255, 0, 0, 0.5},
+		{"color(a98-rgb 0.8590 0 0)", FormatA98RGB, 255, 0, 0, 1},
+		{"color(prophoto-rgb 0.7023 0.2757 0.1036)", FormatProPhotoRGB, 255, 0, 0, 1},
+		{"color(xyz-d50 0.4360 0.2225 0.0139)", FormatXYZD50, 255, 0, 0, 1},
+		{"color(xyz 0.9505 1 1.0888)", FormatXYZD65, 255, 255, 255, 1},
+		{"COLOR(Display-P3 1 1 1 / .25)", FormatDisplayP3, 255, 255, 255, 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			data, err := DetectFormat(tt.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.input, err)
+			}
+			if data.Format != tt.expectFormat {
+				t.Errorf("Expected format %s, got %s", tt.expectFormat, data.Format)
+			}
+			if !almostEqual(data.Color.R, tt.expectR, 0.5) ||
+				!almostEqual(data.Color.G, tt.expectG, 0.5) ||
+				!almostEqual(data.Color.B, tt.expectB, 0.5) {
+				t.Errorf("Expected RGB(%f, %f, %f), got RGB(%f, %f, %f)",
+					tt.expectR, tt.expectG, tt.expectB,
+					data.Color.R, data.Color.G, data.Color.B)
+			}
+			if !almostEqual(data.Color.A, tt.expectAlpha, 0.01) {
+				t.Errorf("Expected A=%f, got %f", tt.expectAlpha, data.Color.A)
+			}
+		})
+	}
+}
+
+// TestOKLabAndLCH tests oklab() and lch() parsing, formatting and round trips
+func TestOKLabAndLCH(t *testing.T) {
+	formatTests := []struct {
+		input        string
+		targetFormat string
+		expected     string
+	}{
+		{"#FF0000", "oklab", "oklab(0.6280 0.2249 0.1258)"},
+		{"#FFFFFF", "oklab", "oklab(1.0000 0.0000 0.0000)"},
+		{"#808080", "lch", "lch(53.59 0.00 0.00)"},
+		{"#FF000080", "oklab", "oklab(0.6280 0.2249 0.1258 / 0.50)"},
+	}
+
+	for _, tt := range formatTests {
+		t.Run(tt.input+"->"+tt.targetFormat, func(t *testing.T) {
+			result, err := Convert(tt.input, tt.targetFormat, true)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	parseTests := []struct {
+		input        string
+		expectFormat ColorFormat
+		reference    string
+	}{
+		{"oklab(0.6280 0.2249 0.1258)", FormatOKLab, "#FF0000"},
+		{"oklab(62.796% 56.216% 31.462%)", FormatOKLab, "#FF0000"},
+		{"oklab(0.5 0 0 / 50%)", FormatOKLab, "oklch(0.5 0 0)"},
+		{"lch(50% 0 0)", FormatLCH, "lab(50 0 0)"},
+		{"lch(60 40 0.25turn)", FormatLCH, "lab(60 0 40)"},
+	}
+
+	for _, tt := range parseTests {
+		t.Run(tt.input, func(t *testing.T) {
+			data, err := DetectFormat(tt.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.input, err)
+			}
+			if data.Format != tt.expectFormat {
+				t.Errorf("Expected format %s, got %s", tt.expectFormat, data.Format)
+			}
+			ref, err := DetectFormat(tt.reference)
+			if err != nil {
+				t.Fatalf("Failed to parse reference %s: %v", tt.reference, err)
+			}
+			if !almostEqual(data.Color.R, ref.Color.R, 0.5) ||
+				!almostEqual(data.Color.G, ref.Color.G, 0.5) ||
+				!almostEqual(data.Color.B, ref.Color.B, 0.5) {
+				t.Errorf("Expected RGB(%f, %f, %f), got RGB(%f, %f, %f)",
+					ref.Color.R, ref.Color.G, ref.Color.B,
+					data.Color.R, data.Color.G, data.Color.B)
+			}
+		})
+	}
+
+	// Round trip through lch must preserve the color
+	for _, hex := range []string{"#3B82F6", "#10B981", "#F59E0B"} {
+		lch, err := Convert(hex, "lch", true)
+		if err != nil {
+			t.Fatalf("Conversion failed: %v", err)
+		}
+		back, err := Convert(lch, "hex", true)
+		if err != nil {
+			t.Fatalf("Conversion failed: %v", err)
+		}
+		if back != hex {
+			t.Errorf("Round trip %s -> %s -> %s", hex, lch, back)
+		}
+	}
+}
+
+// TestWideGamutLossless tests that out-of-sRGB colors survive conversion between wide color spaces
+func TestWideGamutLossless(t *testing.T) {
+	tests := []struct {
+		input        string
+		targetFormat string
+		expected     string
+	}{
+		{"oklch(0.7 0.3 150)", "oklch", "oklch(0.7000 0.3000 150.00)"},
+		{"color(display-p3 0 1 0)", "display-p3", "color(display-p3 0.0000 1.0000 0.0000)"},
+		{"color(rec2020 1 0 0)", "rec2020", "color(rec2020 1.0000 0.0000 0.0000)"},
+		{"color(srgb 1.2 -0.1 0.5)", "srgb", "color(srgb 1.2000 -0.1000 0.5000)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := Convert(tt.input, tt.targetFormat, true)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+
+			// Passing through every other wide space keeps the linear value
+			original, _ := DetectFormat(tt.input)
+			for _, hop := range []string{"oklab", "lab", "lch", "xyz", "xyz-d50", "prophoto-rgb", "rec2020"} {
+				converted, err := Convert(tt.input, hop, true)
+				if err != nil {
+					t.Fatalf("Conversion to %s failed: %v", hop, err)
+				}
+				data, err := DetectFormat(converted)
+				if err != nil {
+					t.Fatalf("Failed to parse %s: %v", converted, err)
+				}
+				if !almostEqual(data.Linear.R, original.Linear.R, 0.005) ||
+					!almostEqual(data.Linear.G, original.Linear.G, 0.005) ||
+					!almostEqual(data.Linear.B, original.Linear.B, 0.005) {
+					t.Errorf("%s lost data: expected linear (%f, %f, %f), got (%f, %f, %f)", converted,
+						original.Linear.R, original.Linear.G, original.Linear.B,
+						data.Linear.R, data.Linear.G, data.Linear.B)
+				}
+			}
+		})
+	}
+
+	// sRGB-based targets still receive values within 0-255
+	data, err := DetectFormat("oklch(0.7 0.3 150)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Color.R < 0 || data.Color.G > RGBMax || data.Color.B < 0 {
+		t.Errorf("Expected clamped sRGB, got RGB(%f, %f, %f)", data.Color.R, data.Color.G, data.Color.B)
+	}
+	if data.Linear.R >= 0 {
+		t.Errorf("Expected negative linear red for out-of-gamut green, got %f", data.Linear.R)
+	}
+}
+
+// TestHighChromaRoundTrip tests that OKLCH chroma above 0.4 is not clamped on input
+func TestHighChromaRoundTrip(t *testing.T) {
+	result, err := Convert("oklch(0.7 0.5 150)", "oklch", true)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if result != "oklch(0.7000 0.5000 150.00)" {
+		t.Errorf("Expected oklch(0.7000 0.5000 150.00), got %s", result)
+	}
+
+	p3, err := Convert("oklch(0.7 0.5 150)", "display-p3", true)
+	if err != nil {
+		t.Fatalf("Conversion to display-p3 failed: %v", err)
+	}
+	back, err := Convert(p3, "oklch", true)
+	if err != nil {
+		t.Fatalf("Conversion from %s failed: %v", p3, err)
+	}
+	if back != "oklch(0.7000 0.5000 150.00)" {
+		t.Errorf("Round trip through %s returned %s", p3, back)
+	}
+}
+
+// TestLinearToRGBIntegerRoundTrip tests that every 8-bit channel value comes
+// back as the same integer after sRGB -> linear -> sRGB, directly and through
+// OKLab and XYZ
+func TestLinearToRGBIntegerRoundTrip(t *testing.T) {
+	for v := 0.0; v <= RGBMax; v++ {
+		lin := srgbInverseGamma(v / RGBMax)
+		if r, _, _ := linearToRGB(lin, 0, 0); r != v {
+			t.Errorf("%v: expected %v after a direct round trip, got %v", v, v, r)
+		}
+
+		g, b := RGBMax-v, math.Floor(v/2)
+		l, okA, okB := linearToOKLab(lin, srgbInverseGamma(g/RGBMax), srgbInverseGamma(b/RGBMax))
+		rLin, gLin, bLin := oklabToLinear(l, okA, okB)
+		if r, gOut, bOut := linearToRGB(rLin, gLin, bLin); r != v || gOut != g || bOut != b {
+			t.Errorf("(%v, %v, %v): expected the same integers through OKLab, got (%v, %v, %v)", v, g, b, r, gOut, bOut)
+		}
+
+		x, y, z := linearToXYZ(lin, lin, lin)
+		rLin, gLin, bLin = xyzToLinear(x, y, z)
+		if r, gOut, bOut := linearToRGB(rLin, gLin, bLin); r != v || gOut != v || bOut != v {
+			t.Errorf("%v: expected gray %v through XYZ, got (%v, %v, %v)", v, v, r, gOut, bOut)
+		}
+	}
+
+	// Genuine fractions are kept
+	if r, _, _ := linearToRGB(srgbInverseGamma(127.5/RGBMax), 0, 0); math.Abs(r-127.5) > 1e-6 || r == 128 {
+		t.Errorf("expected 127.5 unchanged, got %v", r)
+	}
+}
@@ -0,0 +1,372 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CVDType names a color vision deficiency
+type CVDType string
+
+const (
+	CVDProtan        CVDType = "protan"        // missing or anomalous L cones (protanopia, protanomaly)
+	CVDDeutan        CVDType = "deutan"        // missing or anomalous M cones (deuteranopia, deuteranomaly)
+	CVDTritan        CVDType = "tritan"        // missing or anomalous S cones (tritanopia, tritanomaly)
+	CVDAchromatopsia CVDType = "achromatopsia" // no color vision
+)
+
+// cvdMatrices are the Machado et al. (2009) simulation matrices for
+// severities 0, 0.1, ... 1, applied to linear-light sRGB. Severity 1 is
+// dichromacy; lower rows model anomalous trichromacy.
+var cvdMatrices = map[CVDType][11][3][3]float64{
+	CVDProtan: {
+		{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		{{0.856167, 0.182038, -0.038205}, {0.029342, 0.955115, 0.015544}, {-0.002880, -0.001563, 1.004443}},
+		{{0.734766, 0.334872, -0.069637}, {0.051840, 0.919198, 0.028963}, {-0.004928, -0.004209, 1.009137}},
+		{{0.630323, 0.465641, -0.095964}, {0.069181, 0.890046, 0.040773}, {-0.006308, -0.007724, 1.014032}},
+		{{0.539009, 0.579343, -0.118352}, {0.082546, 0.866121, 0.051332}, {-0.007136, -0.011959, 1.019095}},
+		{{0.458064, 0.679578, -0.137642}, {0.092785, 0.846313, 0.060902}, {-0.007494, -0.016807, 1.024301}},
+		{{0.385450, 0.769005, -0.154455}, {0.100526, 0.829802, 0.069673}, {-0.007442, -0.022190, 1.029632}},
+		{{0.319627, 0.849633, -0.169261}, {0.106241, 0.815969, 0.077790}, {-0.007025, -0.028051, 1.035076}},
+		{{0.259411, 0.923008, -0.182420}, {0.110296, 0.804340, 0.085364}, {-0.006276, -0.034346, 1.040622}},
+		{{0.203876, 0.990338, -0.194214}, {0.112975, 0.794542, 0.092483}, {-0.005222, -0.041043, 1.046265}},
+		{{0.152286, 1.052583, -0.204868}, {0.114503, 0.786281, 0.099216}, {-0.003882, -0.048116, 1.051998}},
+	},
+	CVDDeutan: {
+		{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		{{0.866435, 0.177704, -0.044139}, {0.049567, 0.939063, 0.011370}, {-0.003453, 0.007233, 0.996220}},
+		{{0.760729, 0.319078, -0.079807}, {0.090568, 0.889315, 0.020117}, {-0.006027, 0.013325, 0.992702}},
+		{{0.675425, 0.433850, -0.109275}, {0.125303, 0.847755, 0.026942}, {-0.007950, 0.018572, 0.989378}},
+		{{0.605511, 0.528560, -0.134071}, {0.155318, 0.812366, 0.032316}, {-0.009376, 0.023176, 0.986200}},
+		{{0.547494, 0.607765, -0.155259}, {0.181692, 0.781742, 0.036566}, {-0.010410, 0.027275, 0.983136}},
+		{{0.498864, 0.674741, -0.173604}, {0.205199, 0.754872, 0.039929}, {-0.011131, 0.030969, 0.980162}},
+		{{0.457771, 0.731899, -0.189670}, {0.226409, 0.731012, 0.042579}, {-0.011595, 0.034333, 0.977261}},
+		{{0.422823, 0.781057, -0.203881}, {0.245752, 0.709602, 0.044646}, {-0.011843, 0.037423, 0.974421}},
+		{{0.392952, 0.823610, -0.216562}, {0.263559, 0.690210, 0.046232}, {-0.011910, 0.040281, 0.971630}},
+		{{0.367322, 0.860646, -0.227968}, {0.280085, 0.672501, 0.047413}, {-0.011820, 0.042940, 0.968881}},
+	},
+	CVDTritan: {
+		{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		{{0.926670, 0.092514, -0.019184}, {0.021191, 0.964503, 0.014306}, {0.008437, 0.054813, 0.936750}},
+		{{0.895720, 0.133330, -0.029050}, {0.029997, 0.945400, 0.024603}, {0.013027, 0.104707, 0.882266}},
+		{{0.905871, 0.127791, -0.033662}, {0.026856, 0.941251, 0.031893}, {0.013410, 0.148296, 0.838294}},
+		{{0.948035, 0.089490, -0.037526}, {0.014364, 0.946792, 0.038844}, {0.010853, 0.193991, 0.795156}},
+		{{1.017277, 0.027029, -0.044306}, {-0.006113, 0.958479, 0.047634}, {0.006379, 0.248708, 0.744913}},
+		{{1.104996, -0.046633, -0.058363}, {-0.032137, 0.971635, 0.060503}, {0.001336, 0.317922, 0.680742}},
+		{{1.193214, -0.109812, -0.083402}, {-0.058496, 0.979410, 0.079086}, {-0.002346, 0.403492, 0.598854}},
+		{{1.257728, -0.139648, -0.118081}, {-0.078003, 0.975409, 0.102594}, {-0.003316, 0.501214, 0.502102}},
+		{{1.278864, -0.125333, -0.153531}, {-0.084748, 0.957674, 0.127074}, {-0.000989, 0.601151, 0.399838}},
+		{{1.255528, -0.076749, -0.178779}, {-0.078411, 0.930809, 0.147602}, {0.004733, 0.691367, 0.303900}},
+	},
+}
+
+// cvdMatrix interpolates between the two Machado matrices nearest to a
+// severity (0-1)
+func cvdMatrix(t CVDType, severity float64) [3][3]float64 {
+	table := cvdMatrices[t]
+	pos := clamp(severity, 0, 1) * 10
+	i := int(math.Floor(pos))
+	if i >= 10 {
+		return table[10]
+	}
+	f := pos - float64(i)
+	var m [3][3]float64
+	for row := range m {
+		for col := range m[row] {
+			m[row][col] = lerp(table[i][row][col], table[i+1][row][col], f)
+		}
+	}
+	return m
+}
+
+// GetCVDTypes returns the supported color vision deficiencies
+func GetCVDTypes() []string {
+	return []string{string(CVDProtan), string(CVDDeutan), string(CVDTritan), string(CVDAchromatopsia)}
+}
+
+// ParseCVDType validates a deficiency name; the -opia and -omaly names in
+// cvdNames are accepted as aliases of their type
+func ParseCVDType(name string) (CVDType, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	for _, t := range GetCVDTypes() {
+		names := cvdNames[CVDType(t)]
+		if n == t || n == names[0] || n == names[1] {
+			return CVDType(t), nil
+		}
+	}
+	return "", fmt.Errorf("invalid deficiency: %s (supported: %s)", name, strings.Join(GetCVDTypes(), ", "))
+}
+
+// cvdNames holds the clinical names of each type: the dichromacy (severity 1)
+// and the anomalous trichromacy (severity below 1)
+var cvdNames = map[CVDType][2]string{
+	CVDProtan:        {"protanopia", "protanomaly"},
+	CVDDeutan:        {"deuteranopia", "deuteranomaly"},
+	CVDTritan:        {"tritanopia", "tritanomaly"},
+	CVDAchromatopsia: {"achromatopsia", "achromatomaly"},
+}
+
+// Name returns the clinical name of the deficiency at a severity
+func (t CVDType) Name(severity float64) string {
+	if severity < 1 {
+		return cvdNames[t][1]
+	}
+	return cvdNames[t][0]
+}
+
+// simulateCVD returns how a color looks with a color vision deficiency.
+// Severity 1 is dichromacy (or full achromatopsia); lower severities model
+// anomalous trichromacy with the interpolated Machado matrix. Achromatomaly
+// blends the luminance with normal vision.
+func simulateCVD(c Color, t CVDType, severity float64) Color {
+	lc := c.Linear()
+	severity = clamp(severity, 0, 1)
+	var r, g, b float64
+	if t == CVDAchromatopsia {
+		y := srgbToXYZMatrix[1][0]*lc.R + srgbToXYZMatrix[1][1]*lc.G + srgbToXYZMatrix[1][2]*lc.B
+		r, g, b = lerp(lc.R, y, severity), lerp(lc.G, y, severity), lerp(lc.B, y, severity)
+	} else {
+		r, g, b = mulMatrix3(cvdMatrix(t, severity), lc.R, lc.G, lc.B)
+	}
+
+	sim := LinearColor{R: clamp(r, 0, 1), G: clamp(g, 0, 1), B: clamp(b, 0, 1), A: c.A}
+	return sim.SRGB()
+}
+
+// CVDSimulation holds a palette as seen with one deficiency
+type CVDSimulation struct {
+	Type     CVDType
+	Severity float64
+	Colors   []string // simulated colors in the requested format
+	HEX      []string
+}
+
+// CVDResult holds the simulations of a palette
+type CVDResult struct {
+	Colors      []ColorData
+	Format      string
+	Simulations []CVDSimulation
+}
+
+// SimulateCVD simulates how colors look with a deficiency at the given
+// severity (0-1); an empty deficiency simulates every type
+func SimulateCVD(colors []string, deficiency CVDType, severity float64, targetFormat string, opts ConvertOptions) (*CVDResult, error) {
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("at least one color is required")
+	}
+	if severity < 0 || severity > 1 {
+		return nil, fmt.Errorf("severity must be between 0 and 1, got %g", severity)
+	}
+	var types []CVDType
+	if deficiency == "" {
+		for _, name := range GetCVDTypes() {
+			types = append(types, CVDType(name))
+		}
+	} else {
+		t, err := ParseCVDType(string(deficiency))
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	result := &CVDResult{Format: targetFormat}
+	for _, color := range colors {
+		data, err := DetectFormat(color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q: %w", color, err)
+		}
+		result.Colors = append(result.Colors, data)
+	}
+
+	for _, t := range types {
+		sim := CVDSimulation{Type: t, Severity: severity}
+		for _, data := range result.Colors {
+			c := simulateCVD(data.Color, t, severity)
+			formatted, err := FormatColor(ColorData{Color: c, Linear: c.Linear()}, targetFormat, opts)
+			if err != nil {
+				return nil, err
+			}
+			sim.Colors = append(sim.Colors, formatted)
+			sim.HEX = append(sim.HEX, formatHEX(c.R, c.G, c.B, c.A))
+		}
+		result.Simulations = append(result.Simulations, sim)
+	}
+
+	return result, nil
+}
+
+// FormatCVDSimulation formats CVD simulations as text
+func FormatCVDSimulation(result *CVDResult) string {
+	var builder strings.Builder
+	originals := make([]string, len(result.Colors))
+	for i, data := range result.Colors {
+		originals[i] = data.Original
+	}
+	builder.WriteString(fmt.Sprintf("Colors: %s\n", strings.Join(originals, ", ")))
+
+	for _, sim := range result.Simulations {
+		builder.WriteString(fmt.Sprintf("\n%s", sim.Type.Name(sim.Severity)))
+		if sim.Severity < 1 {
+			builder.WriteString(fmt.Sprintf(" (severity %s)", strconv.FormatFloat(sim.Severity, 'f', -1, 64)))
+		}
+		builder.WriteString(":\n")
+		for i, c := range sim.Colors {
+			if c != sim.HEX[i] {
+				c = fmt.Sprintf("%s (%s)", c, sim.HEX[i])
+			}
+			builder.WriteString(fmt.Sprintf("  %s → %s\n", result.Colors[i].Original, c))
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// CVDPair is a pair of palette colors, by index, and their ΔE as simulated
+type CVDPair struct {
+	I, J    int
+	DeltaE  float64 // OKLCH ΔE between the simulated colors
+	Verdict VerdictType
+}
+
+// CVDPaletteCheck holds how distinguishable a palette stays with one deficiency
+type CVDPaletteCheck struct {
+	Type    CVDType // empty for normal vision
+	HEX     []string
+	MinPair CVDPair   // the closest pair
+	Flagged []CVDPair // pairs at or below DeltaESlightlyDifferent, closest first
+	Passes  bool      // no pair at or below DeltaEIndistinguishable
+}
+
+// CVDPaletteResult holds a palette audit across color vision deficiencies
+type CVDPaletteResult struct {
+	Colors   []ColorData
+	Severity float64
+	Normal   CVDPaletteCheck
+	Checks   []CVDPaletteCheck
+	Passes   bool // normal vision and every deficiency pass
+}
+
+// CheckPaletteCVD simulates a palette with each deficiency and checks that
+// every pair of colors stays distinguishable. A deficiency fails when a pair
+// collapses to DeltaEIndistinguishable or less; pairs up to
+// DeltaESlightlyDifferent are flagged as hard to tell apart. Without
+// deficiencies, protan, deutan and tritan are checked.
+func CheckPaletteCVD(colors []string, deficiencies []CVDType, severity float64) (*CVDPaletteResult, error) {
+	if len(colors) < 2 {
+		return nil, fmt.Errorf("at least two colors are required")
+	}
+	if severity < 0 || severity > 1 {
+		return nil, fmt.Errorf("severity must be between 0 and 1, got %g", severity)
+	}
+	if len(deficiencies) == 0 {
+		deficiencies = []CVDType{CVDProtan, CVDDeutan, CVDTritan}
+	}
+
+	result := &CVDPaletteResult{Severity: severity}
+	for _, color := range colors {
+		data, err := DetectFormat(color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q: %w", color, err)
+		}
+		result.Colors = append(result.Colors, data)
+	}
+
+	result.Normal = checkPaletteCVD(result.Colors, "", severity)
+	result.Passes = result.Normal.Passes
+	for _, name := range deficiencies {
+		t, err := ParseCVDType(string(name))
+		if err != nil {
+			return nil, err
+		}
+		check := checkPaletteCVD(result.Colors, t, severity)
+		result.Checks = append(result.Checks, check)
+		result.Passes = result.Passes && check.Passes
+	}
+
+	return result, nil
+}
+
+// checkPaletteCVD compares every pair of a palette simulated with one
+// deficiency; an empty type checks normal vision
+func checkPaletteCVD(colors []ColorData, t CVDType, severity float64) CVDPaletteCheck {
+	check := CVDPaletteCheck{Type: t, Passes: true}
+	simulated := make([]Color, len(colors))
+	for i, data := range colors {
+		simulated[i] = data.Color
+		if t != "" {
+			simulated[i] = simulateCVD(data.Color, t, severity)
+		}
+		c := simulated[i]
+		check.HEX = append(check.HEX, formatHEX(c.R, c.G, c.B, c.A))
+	}
+
+	check.MinPair.DeltaE = math.Inf(1)
+	for i := range simulated {
+		for j := i + 1; j < len(simulated); j++ {
+			deltaE := calculateOKLCHDeltaE(simulated[i], simulated[j])
+			pair := CVDPair{I: i, J: j, DeltaE: deltaE, Verdict: determineVerdict(deltaE)}
+			if deltaE < check.MinPair.DeltaE {
+				check.MinPair = pair
+			}
+			if deltaE <= DeltaESlightlyDifferent {
+				check.Flagged = append(check.Flagged, pair)
+			}
+			if deltaE <= DeltaEIndistinguishable {
+				check.Passes = false
+			}
+		}
+	}
+	sort.SliceStable(check.Flagged, func(a, b int) bool {
+		return check.Flagged[a].DeltaE < check.Flagged[b].DeltaE
+	})
+
+	return check
+}
+
+// FormatPaletteCVD formats a palette CVD audit as text
+func FormatPaletteCVD(result *CVDPaletteResult) string {
+	var builder strings.Builder
+	originals := make([]string, len(result.Colors))
+	for i, data := range result.Colors {
+		originals[i] = data.Original
+	}
+	builder.WriteString(fmt.Sprintf("Palette: %s\n", strings.Join(originals, ", ")))
+	if result.Severity < 1 {
+		builder.WriteString(fmt.Sprintf("Severity: %s\n", strconv.FormatFloat(result.Severity, 'f', -1, 64)))
+	}
+
+	writeCheck := func(name string, check CVDPaletteCheck) {
+		status := "PASS"
+		if !check.Passes {
+			status = "FAIL"
+		}
+		min := check.MinPair
+		builder.WriteString(fmt.Sprintf("\n%s: %s, min ΔE %.4f (%s / %s)\n", name, status, min.DeltaE, originals[min.I], originals[min.J]))
+		if check.Type != "" {
+			builder.WriteString(fmt.Sprintf("  Simulated: %s\n", strings.Join(check.HEX, ", ")))
+		}
+		for _, pair := range check.Flagged {
+			builder.WriteString(fmt.Sprintf("  %s / %s: ΔE %.4f, %s", originals[pair.I], originals[pair.J], pair.DeltaE, pair.Verdict))
+			if check.Type != "" {
+				builder.WriteString(fmt.Sprintf(" (seen as %s / %s)", check.HEX[pair.I], check.HEX[pair.J]))
+			}
+			builder.WriteString("\n")
+		}
+	}
+
+	writeCheck("Normal vision", result.Normal)
+	for _, check := range result.Checks {
+		writeCheck(check.Type.Name(result.Severity), check)
+	}
+
+	if result.Passes {
+		builder.WriteString(fmt.Sprintf("\nResult: PASS (no pair falls to ΔE %.2f or below)", DeltaEIndistinguishable))
+	} else {
+		builder.WriteString(fmt.Sprintf("\nResult: FAIL (some pairs fall to ΔE %.2f or below)", DeltaEIndistinguishable))
+	}
+	return builder.String()
+}
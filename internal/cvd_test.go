@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestParseCVDType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected CVDType
+	}{
+		{"protan", CVDProtan},
+		{"Protanopia", CVDProtan},
+		{"deuteranomaly", CVDDeutan},
+		{"tritanopia", CVDTritan},
+		{"achromatopsia", CVDAchromatopsia},
+		{"achromatomaly", CVDAchromatopsia},
+		{" TRITANOMALY ", CVDTritan},
+		{"deutan", CVDDeutan},
+	}
+	for _, tt := range tests {
+		got, err := ParseCVDType(tt.input)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, input := range []string{"", "red-green", "monochrome", "protfoo", "deuteranomalyyy", "trit", "prot"} {
+		if _, err := ParseCVDType(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestCVDTypeName(t *testing.T) {
+	if got := CVDDeutan.Name(1); got != "deuteranopia" {
+		t.Errorf("expected deuteranopia, got %s", got)
+	}
+	if got := CVDProtan.Name(0.6); got != "protanomaly" {
+		t.Errorf("expected protanomaly, got %s", got)
+	}
+}
+
+func TestSimulateCVD(t *testing.T) {
+	tests := []struct {
+		color      string
+		deficiency CVDType
+		severity   float64
+		hex        string
+	}{
+		{"#ff0000", CVDProtan, 1, "#6D5F00"},
+		{"#00ff00", CVDDeutan, 1, "#EFD63A"},
+		{"#0000ff", CVDTritan, 1, "#006B96"},
+		{"#ff0000", CVDAchromatopsia, 1, "#7F7F7F"},
+		{"#ffffff", CVDProtan, 1, "#FFFFFF"},
+		{"#ff0000", CVDProtan, 0, "#FF0000"},
+		{"#ff0000", CVDProtan, 0.5, "#B45600"},
+	}
+	for _, tt := range tests {
+		result, err := SimulateCVD([]string{tt.color}, tt.deficiency, tt.severity, "hex", ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := result.Simulations[0].Colors[0]; got != tt.hex {
+			t.Errorf("%s %s at %g: expected %s, got %s", tt.color, tt.deficiency, tt.severity, tt.hex, got)
+		}
+	}
+}
+
+func TestCVDMatrix(t *testing.T) {
+	// Severities on the 0.1 grid use Machado's table rows directly
+	if m := cvdMatrix(CVDProtan, 0.5); m[0][0] != 0.458064 {
+		t.Errorf("expected protan R coefficient 0.458064 at 0.5, got %f", m[0][0])
+	}
+	if m := cvdMatrix(CVDDeutan, 1); m != cvdMatrices[CVDDeutan][10] {
+		t.Errorf("expected the deuteranopia matrix at 1, got %v", m)
+	}
+
+	// Severities between rows interpolate their neighbours
+	m := cvdMatrix(CVDTritan, 0.25)
+	low, high := cvdMatrices[CVDTritan][2], cvdMatrices[CVDTritan][3]
+	for row := range m {
+		for col := range m[row] {
+			if !almostEqual(m[row][col], (low[row][col]+high[row][col])/2, 1e-9) {
+				t.Errorf("tritan 0.25 [%d][%d]: expected the mean of rows 0.2 and 0.3, got %f", row, col, m[row][col])
+			}
+		}
+	}
+}
+
+func TestSimulateCVDAllTypes(t *testing.T) {
+	result, err := SimulateCVD([]string{"#3b82f6", "#f97316"}, "", 1, "oklch", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Simulations) != len(GetCVDTypes()) {
+		t.Fatalf("expected %d simulations, got %d", len(GetCVDTypes()), len(result.Simulations))
+	}
+	for _, sim := range result.Simulations {
+		if len(sim.Colors) != 2 || len(sim.HEX) != 2 {
+			t.Errorf("%s: expected 2 colors, got %d", sim.Type, len(sim.Colors))
+		}
+	}
+}
+
+func TestSimulateCVDErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		colors     []string
+		deficiency CVDType
+		severity   float64
+	}{
+		{"no colors", nil, CVDProtan, 1},
+		{"invalid color", []string{"notacolor"}, CVDProtan, 1},
+		{"invalid deficiency", []string{"#ff0000"}, "colorblind", 1},
+		{"severity too high", []string{"#ff0000"}, CVDProtan, 1.5},
+		{"negative severity", []string{"#ff0000"}, CVDProtan, -0.1},
+	}
+	for _, tt := range tests {
+		if _, err := SimulateCVD(tt.colors, tt.deficiency, tt.severity, "hex", ConvertOptions{}); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestCheckPaletteCVD(t *testing.T) {
+	// Tableau 10's orange and green collapse for protanopes
+	result, err := CheckPaletteCVD([]string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728"}, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Normal.Passes {
+		t.Error("expected the palette to pass with normal vision")
+	}
+	if len(result.Checks) != 3 {
+		t.Fatalf("expected 3 default deficiencies, got %d", len(result.Checks))
+	}
+	if result.Passes {
+		t.Error("expected the palette to fail")
+	}
+
+	protan := result.Checks[0]
+	if protan.Type != CVDProtan || protan.Passes {
+		t.Fatalf("expected protan to fail, got %+v", protan)
+	}
+	if protan.MinPair.I != 1 || protan.MinPair.J != 2 || protan.MinPair.DeltaE > DeltaEIndistinguishable {
+		t.Errorf("expected orange/green to collapse, got %+v", protan.MinPair)
+	}
+	if len(protan.Flagged) == 0 || protan.Flagged[0] != protan.MinPair {
+		t.Errorf("expected the closest pair to be flagged first, got %+v", protan.Flagged)
+	}
+
+	for _, check := range result.Checks[1:] {
+		if !check.Passes {
+			t.Errorf("%s: expected pass, min ΔE %.4f", check.Type, check.MinPair.DeltaE)
+		}
+		for _, pair := range check.Flagged {
+			if pair.DeltaE > DeltaESlightlyDifferent || pair.DeltaE < check.MinPair.DeltaE {
+				t.Errorf("%s: unexpected flagged pair %+v", check.Type, pair)
+			}
+		}
+	}
+}
+
+func TestCheckPaletteCVDOkabeIto(t *testing.T) {
+	// The Okabe-Ito palette is designed to stay distinguishable with CVD
+	palette := []string{"#E69F00", "#56B4E9", "#009E73", "#F0E442", "#0072B2", "#D55E00", "#CC79A7", "#000000"}
+	result, err := CheckPaletteCVD(palette, []CVDType{CVDProtan, CVDDeutan, CVDTritan}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passes {
+		t.Errorf("expected Okabe-Ito to pass:\n%s", FormatPaletteCVD(result))
+	}
+}
+
+func TestCheckPaletteCVDErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		colors       []string
+		deficiencies []CVDType
+		severity     float64
+	}{
+		{"one color", []string{"#ff0000"}, nil, 1},
+		{"invalid color", []string{"#ff0000", "notacolor"}, nil, 1},
+		{"invalid deficiency", []string{"#ff0000", "#00ff00"}, []CVDType{"colorblind"}, 1},
+		{"invalid severity", []string{"#ff0000", "#00ff00"}, nil, 2},
+	}
+	for _, tt := range tests {
+		if _, err := CheckPaletteCVD(tt.colors, tt.deficiencies, tt.severity); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DeltaEMetric selects the color difference formula
+type DeltaEMetric string
+
+const (
+	MetricDeltaE76         DeltaEMetric = "deltaE76"          // CIE76, Euclidean distance in CIELAB
+	MetricDeltaE94         DeltaEMetric = "deltaE94"          // CIE94, graphic arts weights
+	MetricDeltaE94Textiles DeltaEMetric = "deltaE94-textiles" // CIE94, textile weights
+	MetricDeltaE2000       DeltaEMetric = "deltaE2000"        // CIEDE2000
+	MetricCMC              DeltaEMetric = "cmc"               // CMC l:c 2:1 (acceptability)
+	MetricCMC11            DeltaEMetric = "cmc1:1"            // CMC l:c 1:1 (perceptibility)
+	MetricDeltaEOK         DeltaEMetric = "deltaEOK"          // Euclidean distance in OKLab
+	MetricDeltaEITP        DeltaEMetric = "deltaEITP"         // ITU-R BT.2124, in ICtCp
+)
+
+// deltaEMetrics lists the metrics in the order they are documented
+var deltaEMetrics = []DeltaEMetric{
+	MetricDeltaE76, MetricDeltaE94, MetricDeltaE94Textiles, MetricDeltaE2000,
+	MetricCMC, MetricCMC11, MetricDeltaEOK, MetricDeltaEITP,
+}
+
+// deltaELabels are the units shown after a ΔE value; OKLab keeps the plain ΔE
+// it has always been reported with
+var deltaELabels = map[DeltaEMetric]string{
+	MetricDeltaE76:         "ΔE76",
+	MetricDeltaE94:         "ΔE94",
+	MetricDeltaE94Textiles: "ΔE94 (textiles)",
+	MetricDeltaE2000:       "ΔE2000",
+	MetricCMC:              "ΔE CMC 2:1",
+	MetricCMC11:            "ΔE CMC 1:1",
+	MetricDeltaEOK:         "ΔE",
+	MetricDeltaEITP:        "ΔE ITP",
+}
+
+// deltaEThresholds holds the indistinguishable and slightly different limits
+// of each metric. The first is the metric's just noticeable difference (about
+// 2.3 for CIE76, 1 for the later formulas and for ΔE ITP by design); the second
+// matches the OKLab 0.10 limit on typical sRGB colors. CIE94 textiles and CMC
+// 2:1 weigh lightness half as much and report smaller values.
+var deltaEThresholds = map[DeltaEMetric][2]float64{
+	MetricDeltaE76:         {2.3, 15},
+	MetricDeltaE94:         {1, 10},
+	MetricDeltaE94Textiles: {1, 6},
+	MetricDeltaE2000:       {1, 10},
+	MetricCMC:              {1, 6},
+	MetricCMC11:            {1, 10},
+	MetricDeltaEOK:         {DeltaEIndistinguishable, DeltaESlightlyDifferent},
+	MetricDeltaEITP:        {1, 25},
+}
+
+// deltaELabel returns the unit shown after a ΔE value of a metric
+func deltaELabel(metric DeltaEMetric) string {
+	if label, ok := deltaELabels[metric]; ok {
+		return label
+	}
+	return "ΔE"
+}
+
+// GetDeltaEMetrics returns the supported color difference metrics
+func GetDeltaEMetrics() []string {
+	names := make([]string, len(deltaEMetrics))
+	for i, m := range deltaEMetrics {
+		names[i] = string(m)
+	}
+	return names
+}
+
+// ParseDeltaEMetric validates a metric name, ignoring case; empty selects deltaEOK
+func ParseDeltaEMetric(name string) (DeltaEMetric, error) {
+	n := strings.TrimSpace(name)
+	if n == "" {
+		return MetricDeltaEOK, nil
+	}
+	for _, m := range deltaEMetrics {
+		if strings.EqualFold(n, string(m)) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("invalid metric: %s (supported: %s)", name, strings.Join(GetDeltaEMetrics(), ", "))
+}
+
+// calculateDeltaE returns the difference between two colors under a metric.
+// It works on the unclamped linear values, so wide-gamut colors are compared
+// as given rather than after clipping to sRGB.
+// CIE94 and CMC are not symmetric: c1 is the reference (standard) color.
+func calculateDeltaE(metric DeltaEMetric, c1, c2 LinearColor) float64 {
+	if metric == MetricDeltaEOK {
+		return deltaEOK(c1, c2)
+	}
+	if metric == MetricDeltaEITP {
+		return deltaEITP(c1, c2)
+	}
+
+	l1, a1, b1 := linearToLAB(c1.R, c1.G, c1.B)
+	l2, a2, b2 := linearToLAB(c2.R, c2.G, c2.B)
+	switch metric {
+	case MetricDeltaE76:
+		return math.Sqrt((l2-l1)*(l2-l1) + (a2-a1)*(a2-a1) + (b2-b1)*(b2-b1))
+	case MetricDeltaE94:
+		return deltaE94(l1, a1, b1, l2, a2, b2, 1, 0.045, 0.015)
+	case MetricDeltaE94Textiles:
+		return deltaE94(l1, a1, b1, l2, a2, b2, 2, 0.048, 0.014)
+	case MetricDeltaE2000:
+		return deltaE2000(l1, a1, b1, l2, a2, b2)
+	case MetricCMC:
+		return deltaECMC(l1, a1, b1, l2, a2, b2, 2, 1)
+	case MetricCMC11:
+		return deltaECMC(l1, a1, b1, l2, a2, b2, 1, 1)
+	default:
+		return deltaEOK(c1, c2)
+	}
+}
+
+// determineMetricVerdict maps a ΔE to a verdict with the thresholds of its metric
+func determineMetricVerdict(metric DeltaEMetric, deltaE float64) VerdictType {
+	thresholds, ok := deltaEThresholds[metric]
+	if !ok {
+		return determineVerdict(deltaE)
+	}
+	switch {
+	case deltaE == DeltaEIdentical:
+		return VerdictIdentical
+	case deltaE <= thresholds[0]:
+		return VerdictIndistinguishable
+	case deltaE <= thresholds[1]:
+		return VerdictSlightlyDifferent
+	default:
+		return VerdictDifferent
+	}
+}
+
+// deltaE94 computes CIE94 with the given lightness weight and chroma and hue
+// factors; (l1, a1, b1) is the reference color
+func deltaE94(l1, a1, b1, l2, a2, b2, kL, k1, k2 float64) float64 {
+	c1 := math.Hypot(a1, b1)
+	c2 := math.Hypot(a2, b2)
+	dL := l1 - l2
+	dC := c1 - c2
+	da := a1 - a2
+	db := b1 - b2
+	// ΔH² can dip below zero from rounding
+	dH2 := math.Max(0, da*da+db*db-dC*dC)
+
+	sC := 1 + k1*c1
+	sH := 1 + k2*c1
+	return math.Sqrt((dL/kL)*(dL/kL) + (dC/sC)*(dC/sC) + dH2/(sH*sH))
+}
+
+// deltaE2000 computes CIEDE2000 (Sharma, Wu and Dalal 2005) with unit
+// parametric factors
+func deltaE2000(l1, a1, b1, l2, a2, b2 float64) float64 {
+	const pow25_7 = 6103515625.0 // 25^7
+
+	cBar := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	cBar7 := math.Pow(cBar, 7)
+	g := 0.5 * (1 - math.Sqrt(cBar7/(cBar7+pow25_7)))
+
+	a1p := (1 + g) * a1
+	a2p := (1 + g) * a2
+	c1p := math.Hypot(a1p, b1)
+	c2p := math.Hypot(a2p, b2)
+	h1p := primeHue(a1p, b1)
+	h2p := primeHue(a2p, b2)
+
+	dLp := l2 - l1
+	dCp := c2p - c1p
+	dhp := 0.0
+	if c1p*c2p != 0 {
+		dhp = h2p - h1p
+		if dhp > 180 {
+			dhp -= 360
+		} else if dhp < -180 {
+			dhp += 360
+		}
+	}
+	dHp := 2 * math.Sqrt(c1p*c2p) * math.Sin(dhp*math.Pi/360)
+
+	lBarP := (l1 + l2) / 2
+	cBarP := (c1p + c2p) / 2
+	hBarP := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hBarP /= 2
+		case hBarP < 360:
+			hBarP = (hBarP + 360) / 2
+		default:
+			hBarP = (hBarP - 360) / 2
+		}
+	}
+
+	rad := math.Pi / 180
+	t := 1 - 0.17*math.Cos((hBarP-30)*rad) + 0.24*math.Cos(2*hBarP*rad) +
+		0.32*math.Cos((3*hBarP+6)*rad) - 0.20*math.Cos((4*hBarP-63)*rad)
+	dTheta := 30 * math.Exp(-((hBarP-275)/25)*((hBarP-275)/25))
+	cBarP7 := math.Pow(cBarP, 7)
+	rC := 2 * math.Sqrt(cBarP7/(cBarP7+pow25_7))
+	l50 := (lBarP - 50) * (lBarP - 50)
+	sL := 1 + 0.015*l50/math.Sqrt(20+l50)
+	sC := 1 + 0.045*cBarP
+	sH := 1 + 0.015*cBarP*t
+	rT := -math.Sin(2*dTheta*rad) * rC
+
+	fL := dLp / sL
+	fC := dCp / sC
+	fH := dHp / sH
+	return math.Sqrt(fL*fL + fC*fC + fH*fH + rT*fC*fH)
+}
+
+// primeHue returns the hue angle in degrees (0-360) of an a, b pair, 0 for neutrals
+func primeHue(a, b float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	return normalizeHue(math.Atan2(b, a) * 180 / math.Pi)
+}
+
+// deltaECMC computes CMC l:c; (l1, a1, b1) is the reference color
+func deltaECMC(l1, a1, b1, l2, a2, b2, l, c float64) float64 {
+	c1 := math.Hypot(a1, b1)
+	c2 := math.Hypot(a2, b2)
+	h1 := primeHue(a1, b1)
+
+	dL := l1 - l2
+	dC := c1 - c2
+	da := a1 - a2
+	db := b1 - b2
+	dH2 := math.Max(0, da*da+db*db-dC*dC)
+
+	rad := math.Pi / 180
+	var t float64
+	if h1 >= 164 && h1 <= 345 {
+		t = 0.56 + math.Abs(0.2*math.Cos((h1+168)*rad))
+	} else {
+		t = 0.36 + math.Abs(0.4*math.Cos((h1+35)*rad))
+	}
+	c14 := c1 * c1 * c1 * c1
+	f := math.Sqrt(c14 / (c14 + 1900))
+
+	sL := 0.511
+	if l1 >= 16 {
+		sL = 0.040975 * l1 / (1 + 0.01765*l1)
+	}
+	sC := 0.0638*c1/(1+0.0131*c1) + 0.638
+	sH := sC * (f*t + 1 - f)
+
+	return math.Sqrt((dL/(l*sL))*(dL/(l*sL)) + (dC/(c*sC))*(dC/(c*sC)) + dH2/(sH*sH))
+}
+
+// ICtCp constants (ITU-R BT.2100 PQ)
+const (
+	itpSDRWhite = 203.0 // cd/m² of SDR reference white (ITU-R BT.2408)
+	pqMaxLum    = 10000.0
+	pqM1        = 2610.0 / 16384
+	pqM2        = 2523.0 / 4096 * 128
+	pqC1        = 3424.0 / 4096
+	pqC2        = 2413.0 / 4096 * 32
+	pqC3        = 2392.0 / 4096 * 32
+)
+
+// deltaEITP computes ΔE ITP (ITU-R BT.2124) with SDR white at 203 cd/m²
+func deltaEITP(c1, c2 LinearColor) float64 {
+	i1, t1, p1 := linearToICtCp(c1.R, c1.G, c1.B)
+	i2, t2, p2 := linearToICtCp(c2.R, c2.G, c2.B)
+	// T is half of Ct
+	dI := i1 - i2
+	dT := 0.5 * (t1 - t2)
+	dP := p1 - p2
+	return 720 * math.Sqrt(dI*dI+dT*dT+dP*dP)
+}
+
+// linearToICtCp converts linear sRGB to BT.2100 ICtCp via linear Rec. 2020
+func linearToICtCp(r, g, b float64) (i, ct, cp float64) {
+	x, y, z := linearToXYZ(r, g, b)
+	r, g, b = mulMatrix3(xyzToRec2020Matrix, x, y, z)
+	scale := itpSDRWhite / pqMaxLum
+	r, g, b = r*scale, g*scale, b*scale
+
+	l := pqEncode((1688*r + 2146*g + 262*b) / 4096)
+	m := pqEncode((683*r + 2951*g + 462*b) / 4096)
+	s := pqEncode((99*r + 309*g + 3688*b) / 4096)
+
+	i = 0.5*l + 0.5*m
+	ct = (6610*l - 13613*m + 7003*s) / 4096
+	cp = (17933*l - 17390*m - 543*s) / 4096
+	return i, ct, cp
+}
+
+// pqEncode applies the SMPTE ST 2084 (PQ) inverse EOTF to a luminance
+// normalized to 10000 cd/m²
+func pqEncode(v float64) float64 {
+	v = math.Max(v, 0)
+	vm1 := math.Pow(v, pqM1)
+	return math.Pow((pqC1+pqC2*vm1)/(1+pqC3*vm1), pqM2)
+}
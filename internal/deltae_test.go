@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseDeltaEMetric(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected DeltaEMetric
+	}{
+		{"", MetricDeltaEOK},
+		{"deltaE2000", MetricDeltaE2000},
+		{"DELTAE76", MetricDeltaE76},
+		{"cmc", MetricCMC},
+		{"CMC1:1", MetricCMC11},
+		{"deltaEITP", MetricDeltaEITP},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeltaEMetric(tt.input)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	if _, err := ParseDeltaEMetric("deltaE99"); err == nil {
+		t.Error("expected error for deltaE99")
+	}
+}
+
+func TestDeltaE2000Sharma(t *testing.T) {
+	// Test data from Sharma, Wu and Dalal (2005)
+	tests := []struct {
+		lab1, lab2 [3]float64
+		expected   float64
+	}{
+		{[3]float64{50, 2.6772, -79.7751}, [3]float64{50, 0, -82.7485}, 2.0425},
+		{[3]float64{50, 0, 0}, [3]float64{50, -1, 2}, 2.3669},
+		{[3]float64{50, 2.49, -0.001}, [3]float64{50, -2.49, 0.0011}, 7.2195},
+		{[3]float64{50, 2.5, 0}, [3]float64{73, 25, -18}, 27.1492},
+		{[3]float64{60.2574, -34.0099, 36.2677}, [3]float64{60.4626, -34.1751, 39.4387}, 1.2644},
+		{[3]float64{2.0776, 0.0795, -1.135}, [3]float64{0.9033, -0.0636, -0.5514}, 0.9082},
+	}
+	for _, tt := range tests {
+		got := deltaE2000(tt.lab1[0], tt.lab1[1], tt.lab1[2], tt.lab2[0], tt.lab2[1], tt.lab2[2])
+		if math.Abs(got-tt.expected) > 1e-4 {
+			t.Errorf("%v vs %v: expected %.4f, got %.4f", tt.lab1, tt.lab2, tt.expected, got)
+		}
+		// CIEDE2000 is symmetric
+		if back := deltaE2000(tt.lab2[0], tt.lab2[1], tt.lab2[2], tt.lab1[0], tt.lab1[1], tt.lab1[2]); math.Abs(back-got) > 1e-9 {
+			t.Errorf("%v vs %v: not symmetric (%.6f vs %.6f)", tt.lab1, tt.lab2, got, back)
+		}
+	}
+}
+
+func TestDeltaECIELABFormulas(t *testing.T) {
+	// Pure lightness differences: CIE94 and CMC divide by kL and SL
+	if got := deltaE94(50, 0, 0, 60, 0, 0, 1, 0.045, 0.015); math.Abs(got-10) > 1e-9 {
+		t.Errorf("CIE94 graphic arts: expected 10, got %.4f", got)
+	}
+	if got := deltaE94(50, 0, 0, 60, 0, 0, 2, 0.048, 0.014); math.Abs(got-5) > 1e-9 {
+		t.Errorf("CIE94 textiles: expected 5, got %.4f", got)
+	}
+	sL := 0.040975 * 50 / (1 + 0.01765*50)
+	if got := deltaECMC(50, 0, 0, 60, 0, 0, 2, 1); math.Abs(got-10/(2*sL)) > 1e-9 {
+		t.Errorf("CMC 2:1: expected %.4f, got %.4f", 10/(2*sL), got)
+	}
+
+	// CIE94 and CMC weigh chroma by the reference color, so they are not symmetric
+	ab := deltaE94(50, 60, 0, 50, 40, 0, 1, 0.045, 0.015)
+	ba := deltaE94(50, 40, 0, 50, 60, 0, 1, 0.045, 0.015)
+	if ab == ba {
+		t.Error("expected CIE94 to depend on the reference color")
+	}
+}
+
+func TestCalculateDeltaE(t *testing.T) {
+	black, white := LinearColor{A: 1}, LinearColor{R: 1, G: 1, B: 1, A: 1}
+	red := LinearColor{R: 1, A: 1}
+	for _, metric := range deltaEMetrics {
+		if got := calculateDeltaE(metric, red, red); got != 0 {
+			t.Errorf("%s: expected 0 for identical colors, got %g", metric, got)
+		}
+		if got := calculateDeltaE(metric, black, white); got <= 0 {
+			t.Errorf("%s: expected a positive black/white difference, got %g", metric, got)
+		}
+	}
+
+	if got := calculateDeltaE(MetricDeltaE76, black, white); math.Abs(got-100) > 1e-6 {
+		t.Errorf("deltaE76 black/white: expected 100, got %.4f", got)
+	}
+	if got := calculateDeltaE(MetricDeltaEOK, black, white); math.Abs(got-1) > 1e-4 {
+		t.Errorf("deltaEOK black/white: expected 1, got %.4f", got)
+	}
+}
+
+func TestDetermineMetricVerdict(t *testing.T) {
+	tests := []struct {
+		metric   DeltaEMetric
+		deltaE   float64
+		expected VerdictType
+	}{
+		{MetricDeltaE2000, 0, VerdictIdentical},
+		{MetricDeltaE2000, 0.8, VerdictIndistinguishable},
+		{MetricDeltaE2000, 3, VerdictSlightlyDifferent},
+		{MetricDeltaE2000, 12, VerdictDifferent},
+		{MetricDeltaE76, 2, VerdictIndistinguishable},
+		{MetricDeltaEOK, 0.05, VerdictSlightlyDifferent},
+		{MetricDeltaEOK, 0.5, VerdictDifferent},
+		{MetricDeltaEITP, 20, VerdictSlightlyDifferent},
+	}
+	for _, tt := range tests {
+		if got := determineMetricVerdict(tt.metric, tt.deltaE); got != tt.expected {
+			t.Errorf("%s %g: expected %s, got %s", tt.metric, tt.deltaE, tt.expected, got)
+		}
+	}
+}
+
+func TestCompareColorsMetric(t *testing.T) {
+	result, err := CompareColors("#3b82f6", "#2c72e5", MetricDeltaE2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Metric != MetricDeltaE2000 {
+		t.Errorf("expected metric deltaE2000, got %s", result.Metric)
+	}
+	if math.Abs(result.PerceptualDiff-5.989) > 0.01 {
+		t.Errorf("expected ΔE2000 about 5.989, got %.4f", result.PerceptualDiff)
+	}
+	if result.Verdict != VerdictSlightlyDifferent {
+		t.Errorf("expected slightly different, got %s", result.Verdict)
+	}
+	if text := FormatComparisonBasic(result); !strings.Contains(text, "5.989 ΔE2000") {
+		t.Errorf("expected the metric in the output:\n%s", text)
+	}
+
+	if _, err := CompareColors("#3b82f6", "#2c72e5", "deltaE99"); err == nil {
+		t.Error("expected error for an invalid metric")
+	}
+}
+
+func TestCompareColorsWideGamut(t *testing.T) {
+	// Both greens clip to #00FF00 in sRGB; the metrics must see the unclamped values
+	for _, metric := range deltaEMetrics {
+		result, err := CompareColors("color(display-p3 0 1 0)", "color(rec2020 0 1 0)", metric)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Verdict != VerdictDifferent && result.Verdict != VerdictSlightlyDifferent {
+			t.Errorf("%s: expected a visible difference, got %.4f (%s)", metric, result.PerceptualDiff, result.Verdict)
+		}
+	}
+
+	result, err := CompareColors("color(display-p3 0 1 0)", "color(rec2020 0 1 0)", MetricDeltaEOK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PerceptualDiff < 0.05 {
+		t.Errorf("expected a ΔEOK above 0.05, got %.4f", result.PerceptualDiff)
+	}
+}
@@ -0,0 +1,430 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register the GIF decoder
+	_ "image/jpeg" // register the JPEG decoder
+	_ "image/png"  // register the PNG decoder
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ExtractMethod selects how extract_palette finds dominant colors
+type ExtractMethod string
+
+const (
+	ExtractKMeans    ExtractMethod = "kmeans"     // weighted k-means in OKLab
+	ExtractMedianCut ExtractMethod = "median-cut" // variance-based median cut of OKLab boxes
+)
+
+// Palette extraction limits
+const (
+	DefaultExtractCount = 5
+	MaxExtractCount     = 32
+	maxExtractSamples   = 65536 // pixels sampled from large images
+	extractAlphaMin     = 128   // pixels more transparent than this are skipped
+	maxImagePixels      = 50_000_000
+)
+
+// maxImageBytes caps the encoded image read from a file or base64 data
+var maxImageBytes = 64 << 20
+
+// GetExtractMethods returns the supported palette extraction methods
+func GetExtractMethods() []string {
+	return []string{string(ExtractKMeans), string(ExtractMedianCut)}
+}
+
+// ParseExtractMethod validates an extraction method name; empty selects kmeans
+func ParseExtractMethod(name string) (ExtractMethod, error) {
+	switch m := ExtractMethod(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return ExtractKMeans, nil
+	case ExtractKMeans, ExtractMedianCut:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid extraction method: %s (supported: %s)", name, strings.Join(GetExtractMethods(), ", "))
+	}
+}
+
+// LoadImage decodes a PNG, JPEG or GIF from a file path or from base64 data,
+// optionally as a data: URL. It returns the image and its format name.
+// Inputs over 64 MiB and images over 50 megapixels are rejected before
+// decoding.
+func LoadImage(path, data string) (image.Image, string, error) {
+	var raw []byte
+	switch {
+	case path != "" && data != "":
+		return nil, "", fmt.Errorf("use either path or data, not both")
+	case path != "":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("cannot read image: %w", err)
+		}
+		defer f.Close()
+		b, err := io.ReadAll(io.LimitReader(f, int64(maxImageBytes)+1))
+		if err != nil {
+			return nil, "", fmt.Errorf("cannot read image: %w", err)
+		}
+		if len(b) > maxImageBytes {
+			return nil, "", fmt.Errorf("image file exceeds %d bytes", maxImageBytes)
+		}
+		raw = b
+	case data != "":
+		encoded := strings.TrimSpace(data)
+		if strings.HasPrefix(encoded, "data:") {
+			_, after, found := strings.Cut(encoded, ",")
+			if !found {
+				return nil, "", fmt.Errorf("invalid data URL")
+			}
+			encoded = after
+		}
+		if len(encoded) > base64.StdEncoding.EncodedLen(maxImageBytes) {
+			return nil, "", fmt.Errorf("image data exceeds %d bytes", maxImageBytes)
+		}
+		b, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 image data: %w", err)
+		}
+		raw = b
+	default:
+		return nil, "", fmt.Errorf("an image path or base64 data is required")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot decode image (supported: png, jpeg, gif): %w", err)
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > maxImagePixels {
+		return nil, "", fmt.Errorf("image is %dx%d, over the %d megapixel limit", config.Width, config.Height, maxImagePixels/1_000_000)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot decode image (supported: png, jpeg, gif): %w", err)
+	}
+	return img, format, nil
+}
+
+// ExtractedColor is one dominant color of an image
+type ExtractedColor struct {
+	Color string // in the requested format
+	HEX   string
+	Share float64 // fraction of the sampled pixels (0-1)
+}
+
+// ExtractResult holds the dominant colors of an image
+type ExtractResult struct {
+	Format        string // image format
+	Width, Height int
+	Sampled       int // opaque pixels sampled
+	Method        ExtractMethod
+	Colors        []ExtractedColor
+}
+
+// weightedColor is a distinct pixel color in OKLab with its pixel count
+type weightedColor struct {
+	lab    [3]float64
+	weight float64
+}
+
+// ExtractPalette finds the k dominant colors of an image in OKLab, with each
+// color's share of the pixels, largest first. Large images are sampled on a
+// regular grid and mostly transparent pixels are skipped. Fewer than k colors
+// are returned when the image has fewer distinct colors.
+func ExtractPalette(img image.Image, format string, k int, method ExtractMethod, targetFormat string, opts ConvertOptions) (*ExtractResult, error) {
+	if k == 0 {
+		k = DefaultExtractCount
+	}
+	if k < 1 || k > MaxExtractCount {
+		return nil, fmt.Errorf("count must be between 1 and %d, got %d", MaxExtractCount, k)
+	}
+	method, err := ParseExtractMethod(string(method))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	result := &ExtractResult{Format: format, Width: bounds.Dx(), Height: bounds.Dy(), Method: method}
+	colors, sampled := samplePixels(img)
+	if sampled == 0 {
+		return nil, fmt.Errorf("the image has no opaque pixels")
+	}
+	result.Sampled = sampled
+
+	var groups []weightedColor
+	if method == ExtractMedianCut {
+		groups = medianCut(colors, k)
+	} else {
+		groups = weightedKMeans(colors, k)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].weight > groups[j].weight
+	})
+
+	for _, g := range groups {
+		r, gr, b := oklabToLinear(g.lab[0], g.lab[1], g.lab[2])
+		c := LinearColor{R: r, G: gr, B: b, A: AlphaMax}.SRGB()
+		c = Color{R: math.Round(c.R), G: math.Round(c.G), B: math.Round(c.B), A: AlphaMax}
+		formatted, err := FormatColor(ColorData{Color: c, Linear: c.Linear()}, targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Colors = append(result.Colors, ExtractedColor{
+			Color: formatted,
+			HEX:   formatHEX(c.R, c.G, c.B, c.A),
+			Share: g.weight / float64(sampled),
+		})
+	}
+
+	return result, nil
+}
+
+// samplePixels returns the distinct opaque colors of an image with their pixel
+// counts, sampling at most about maxExtractSamples pixels on a regular grid
+func samplePixels(img image.Image) ([]weightedColor, int) {
+	bounds := img.Bounds()
+	step := 1
+	if pixels := bounds.Dx() * bounds.Dy(); pixels > maxExtractSamples {
+		step = int(math.Ceil(math.Sqrt(float64(pixels) / maxExtractSamples)))
+	}
+
+	counts := make(map[[3]uint8]int)
+	var order [][3]uint8
+	sampled := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < extractAlphaMin {
+				continue
+			}
+			key := [3]uint8{c.R, c.G, c.B}
+			if counts[key] == 0 {
+				order = append(order, key)
+			}
+			counts[key]++
+			sampled++
+		}
+	}
+
+	colors := make([]weightedColor, len(order))
+	for i, key := range order {
+		c := Color{R: float64(key[0]), G: float64(key[1]), B: float64(key[2]), A: AlphaMax}
+		colors[i] = weightedColor{lab: colorToOKLab(c), weight: float64(counts[key])}
+	}
+	return colors, sampled
+}
+
+// weightedMean returns the pixel-weighted mean of colors in OKLab
+func weightedMean(colors []weightedColor) weightedColor {
+	var mean weightedColor
+	for _, c := range colors {
+		for i := range mean.lab {
+			mean.lab[i] += c.lab[i] * c.weight
+		}
+		mean.weight += c.weight
+	}
+	for i := range mean.lab {
+		mean.lab[i] /= mean.weight
+	}
+	return mean
+}
+
+// weightedKMeans clusters colors by pixel-weighted k-means. Seeds are chosen
+// deterministically: the most frequent color, then repeatedly the color with
+// the largest weight times squared distance to the nearest seed.
+func weightedKMeans(colors []weightedColor, k int) []weightedColor {
+	if k > len(colors) {
+		k = len(colors)
+	}
+
+	seed := 0
+	for i, c := range colors {
+		if c.weight > colors[seed].weight {
+			seed = i
+		}
+	}
+	centers := [][3]float64{colors[seed].lab}
+	nearest := make([]float64, len(colors))
+	for i, c := range colors {
+		d := oklabDistance(c.lab, centers[0])
+		nearest[i] = d * d
+	}
+	for len(centers) < k {
+		best, bestScore := -1, 0.0
+		for i, c := range colors {
+			if score := c.weight * nearest[i]; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		centers = append(centers, colors[best].lab)
+		for i, c := range colors {
+			d := oklabDistance(c.lab, colors[best].lab)
+			nearest[i] = math.Min(nearest[i], d*d)
+		}
+	}
+
+	assign := make([]int, len(colors))
+	var groups [][]weightedColor
+	for iter := 0; iter < maxKMeansIterations; iter++ {
+		changed := iter == 0
+		for i, c := range colors {
+			closest, closestDist := 0, math.Inf(1)
+			for j, center := range centers {
+				if d := oklabDistance(c.lab, center); d < closestDist {
+					closest, closestDist = j, d
+				}
+			}
+			if assign[i] != closest {
+				assign[i] = closest
+				changed = true
+			}
+		}
+
+		groups = make([][]weightedColor, len(centers))
+		for i, c := range colors {
+			groups[assign[i]] = append(groups[assign[i]], c)
+		}
+		if !changed {
+			break
+		}
+		for j, g := range groups {
+			if len(g) > 0 {
+				centers[j] = weightedMean(g).lab
+			}
+		}
+	}
+
+	var result []weightedColor
+	for _, g := range groups {
+		if len(g) > 0 {
+			result = append(result, weightedMean(g))
+		}
+	}
+	return result
+}
+
+// medianCut quantizes colors with the variance-based median cut of Wan, Wong
+// and Prusinkiewicz (1988): the OKLab box with the largest pixel-weighted
+// squared error is split along its axis of largest variance, at the cut that
+// minimizes the error of the two halves, until there are k boxes. Each box is
+// represented by its weighted mean.
+func medianCut(colors []weightedColor, k int) []weightedColor {
+	boxes := [][]weightedColor{colors}
+	for len(boxes) < k {
+		best, bestError := -1, 0.0
+		for i, box := range boxes {
+			if e := boxError(box); len(box) > 1 && e > bestError {
+				best, bestError = i, e
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		axis := varianceAxis(box)
+		sort.SliceStable(box, func(i, j int) bool {
+			return box[i].lab[axis] < box[j].lab[axis]
+		})
+		cut := bestCut(box)
+		boxes[best] = box[:cut]
+		boxes = append(boxes, box[cut:])
+	}
+
+	result := make([]weightedColor, len(boxes))
+	for i, box := range boxes {
+		result[i] = weightedMean(box)
+	}
+	return result
+}
+
+// boxError returns the pixel-weighted squared distance of a box's colors to their mean
+func boxError(box []weightedColor) float64 {
+	mean := weightedMean(box)
+	e := 0.0
+	for _, c := range box {
+		d := oklabDistance(c.lab, mean.lab)
+		e += c.weight * d * d
+	}
+	return e
+}
+
+// varianceAxis returns the OKLab axis along which a box has the largest weighted variance
+func varianceAxis(box []weightedColor) int {
+	mean := weightedMean(box)
+	axis, largest := 0, -1.0
+	for a := 0; a < 3; a++ {
+		v := 0.0
+		for _, c := range box {
+			d := c.lab[a] - mean.lab[a]
+			v += c.weight * d * d
+		}
+		if v > largest {
+			axis, largest = a, v
+		}
+	}
+	return axis
+}
+
+// bestCut returns the index splitting a sorted box into two non-empty halves
+// with the smallest total squared error, using prefix sums
+func bestCut(box []weightedColor) int {
+	var total weightedColor
+	totalSq := 0.0
+	for _, c := range box {
+		for i := range total.lab {
+			total.lab[i] += c.weight * c.lab[i]
+		}
+		total.weight += c.weight
+		totalSq += c.weight * (c.lab[0]*c.lab[0] + c.lab[1]*c.lab[1] + c.lab[2]*c.lab[2])
+	}
+
+	// Squared error of a group from its weight, weighted sum and weighted sum of squares
+	sse := func(w float64, sum [3]float64, sq float64) float64 {
+		return sq - (sum[0]*sum[0]+sum[1]*sum[1]+sum[2]*sum[2])/w
+	}
+
+	cut, cutError := 1, math.Inf(1)
+	var left weightedColor
+	leftSq := 0.0
+	for i := 0; i < len(box)-1; i++ {
+		c := box[i]
+		for j := range left.lab {
+			left.lab[j] += c.weight * c.lab[j]
+		}
+		left.weight += c.weight
+		leftSq += c.weight * (c.lab[0]*c.lab[0] + c.lab[1]*c.lab[1] + c.lab[2]*c.lab[2])
+
+		right := [3]float64{total.lab[0] - left.lab[0], total.lab[1] - left.lab[1], total.lab[2] - left.lab[2]}
+		e := sse(left.weight, left.lab, leftSq) + sse(total.weight-left.weight, right, totalSq-leftSq)
+		if e < cutError {
+			cut, cutError = i+1, e
+		}
+	}
+	return cut
+}
+
+// FormatExtractedPalette formats the dominant colors of an image as text
+func FormatExtractedPalette(result *ExtractResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Image: %s, %d×%d, %d pixels sampled\n", result.Format, result.Width, result.Height, result.Sampled))
+	builder.WriteString(fmt.Sprintf("Dominant colors (%s in OKLab):\n", result.Method))
+	for i, c := range result.Colors {
+		color := c.Color
+		if c.Color != c.HEX {
+			color = fmt.Sprintf("%s (%s)", c.Color, c.HEX)
+		}
+		builder.WriteString(fmt.Sprintf("  %d. %s  %.1f%%\n", i+1, color, c.Share*100))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
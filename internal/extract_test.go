@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stripedImage is 100×100 with 60 rows of blue, 30 of orange and 10 of white
+func stripedImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		c := color.NRGBA{R: 0x3B, G: 0x82, B: 0xF6, A: 255}
+		if y >= 60 {
+			c = color.NRGBA{R: 0xF9, G: 0x73, B: 0x16, A: 255}
+		}
+		if y >= 90 {
+			c = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		for x := 0; x < 100; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestExtractPalette(t *testing.T) {
+	expected := []struct {
+		hex   string
+		share float64
+	}{{"#3B82F6", 0.6}, {"#F97316", 0.3}, {"#FFFFFF", 0.1}}
+
+	for _, method := range []ExtractMethod{ExtractKMeans, ExtractMedianCut} {
+		t.Run(string(method), func(t *testing.T) {
+			result, err := ExtractPalette(stripedImage(), "png", 3, method, "hex", ConvertOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Sampled != 10000 || len(result.Colors) != 3 {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			for i, e := range expected {
+				c := result.Colors[i]
+				if c.Color != e.hex || math.Abs(c.Share-e.share) > 1e-9 {
+					t.Errorf("color %d: expected %s at %.0f%%, got %s at %.1f%%", i, e.hex, e.share*100, c.Color, c.Share*100)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractPaletteMerges(t *testing.T) {
+	// Two colors requested from three: blue stays, orange and white merge
+	for _, method := range []ExtractMethod{ExtractKMeans, ExtractMedianCut} {
+		result, err := ExtractPalette(stripedImage(), "png", 2, method, "hex", ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Colors) != 2 || result.Colors[0].HEX != "#3B82F6" || math.Abs(result.Colors[1].Share-0.4) > 1e-9 {
+			t.Errorf("%s: unexpected colors %+v", method, result.Colors)
+		}
+	}
+
+	// Asking for more colors than the image has returns the distinct colors
+	result, err := ExtractPalette(stripedImage(), "png", 8, "", "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Colors) != 3 {
+		t.Errorf("expected 3 colors, got %d", len(result.Colors))
+	}
+}
+
+func TestExtractPaletteSkipsTransparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for i := 0; i < 10; i++ {
+		img.SetNRGBA(i, 0, color.NRGBA{R: 255, A: 255})
+	}
+	result, err := ExtractPalette(img, "png", 3, "", "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sampled != 10 || len(result.Colors) != 1 || result.Colors[0].HEX != "#FF0000" || result.Colors[0].Share != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := ExtractPalette(image.NewNRGBA(image.Rect(0, 0, 4, 4)), "png", 3, "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for a fully transparent image")
+	}
+}
+
+func TestExtractPaletteSamplesLargeImages(t *testing.T) {
+	large := image.NewGray(image.Rect(0, 0, 1000, 1000))
+	for i := range large.Pix {
+		large.Pix[i] = 0x80
+	}
+	result, err := ExtractPalette(large, "png", 2, "", "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sampled > maxExtractSamples || result.Colors[0].HEX != "#808080" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestLoadImage(t *testing.T) {
+	var pngData, jpegData, gifData bytes.Buffer
+	if err := png.Encode(&pngData, stripedImage()); err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(&jpegData, stripedImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.Encode(&gifData, stripedImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "stripes.png")
+	if err := os.WriteFile(path, pngData.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path, data string
+		format     string
+	}{
+		{"path", path, "", "png"},
+		{"base64", "", base64.StdEncoding.EncodeToString(jpegData.Bytes()), "jpeg"},
+		{"data URL", "", "data:image/gif;base64," + base64.StdEncoding.EncodeToString(gifData.Bytes()), "gif"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, format, err := LoadImage(tt.path, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format || img.Bounds().Dx() != 100 {
+				t.Errorf("expected a 100px %s, got %s %v", tt.format, format, img.Bounds())
+			}
+		})
+	}
+
+	errors := []struct {
+		name       string
+		path, data string
+	}{
+		{"nothing", "", ""},
+		{"both", path, "aGVsbG8="},
+		{"missing file", filepath.Join(t.TempDir(), "missing.png"), ""},
+		{"bad base64", "", "not base64!"},
+		{"not an image", "", base64.StdEncoding.EncodeToString([]byte("hello"))},
+	}
+	for _, tt := range errors {
+		if _, _, err := LoadImage(tt.path, tt.data); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestLoadImageLimits(t *testing.T) {
+	// A GIF header declaring a 10000x10000 logical screen is rejected
+	// before any pixel memory is allocated
+	huge := []byte("GIF89a\x10\x27\x10\x27\x00\x00\x00;")
+	if _, _, err := LoadImage("", base64.StdEncoding.EncodeToString(huge)); err == nil || !strings.Contains(err.Error(), "megapixel") {
+		t.Errorf("expected a megapixel limit error, got %v", err)
+	}
+
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, stripedImage()); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "stripes.png")
+	if err := os.WriteFile(path, pngData.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := maxImageBytes
+	maxImageBytes = pngData.Len() / 2
+	defer func() { maxImageBytes = saved }()
+
+	if _, _, err := LoadImage(path, ""); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("expected a file size error, got %v", err)
+	}
+	if _, _, err := LoadImage("", base64.StdEncoding.EncodeToString(pngData.Bytes())); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("expected a data size error, got %v", err)
+	}
+}
+
+func TestExtractPaletteErrors(t *testing.T) {
+	if _, err := ExtractPalette(stripedImage(), "png", MaxExtractCount+1, "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for too many colors")
+	}
+	if _, err := ExtractPalette(stripedImage(), "png", 3, "octree", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for an invalid method")
+	}
+}
@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// GamutMapping selects how colors outside a target gamut are brought inside it
+type GamutMapping string
+
+const (
+	GamutMappingClip         GamutMapping = "clip"          // clamp each channel on its own (may shift hue)
+	GamutMappingCSS4         GamutMapping = "css4"          // CSS Color 4: reduce OKLCH chroma, accept clipping within the JND
+	GamutMappingChromaReduce GamutMapping = "chroma-reduce" // reduce OKLCH chroma until the color fits, never clip
+)
+
+// GetGamutMappings returns the supported gamut mapping methods
+func GetGamutMappings() []string {
+	return []string{
+		string(GamutMappingClip),
+		string(GamutMappingCSS4),
+		string(GamutMappingChromaReduce),
+	}
+}
+
+// ParseGamutMapping validates a gamut mapping method name; empty selects clip
+func ParseGamutMapping(method string) (GamutMapping, error) {
+	switch m := GamutMapping(strings.ToLower(strings.TrimSpace(method))); m {
+	case "":
+		return GamutMappingClip, nil
+	case GamutMappingClip, GamutMappingCSS4, GamutMappingChromaReduce:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid gamut mapping: %s (supported: %s)", method, strings.Join(GetGamutMappings(), ", "))
+	}
+}
+
+// inGamut reports whether a linear sRGB color fits inside an RGB color space
+func inGamut(c LinearColor, space predefinedSpace) bool {
+	c1, c2, c3 := linearToPredefined(space, c.R, c.G, c.B)
+	return inUnitRange(c1) && inUnitRange(c2) && inUnitRange(c3)
+}
+
+func inUnitRange(v float64) bool {
+	return v >= -gamutTolerance && v <= 1+gamutTolerance
+}
+
+// clipToGamut clamps each channel of the color in the target space to 0-1
+func clipToGamut(c LinearColor, space predefinedSpace) LinearColor {
+	c1, c2, c3 := linearToPredefined(space, c.R, c.G, c.B)
+	r, g, b := predefinedToLinear(space, clamp(c1, 0, 1), clamp(c2, 0, 1), clamp(c3, 0, 1))
+	return LinearColor{R: r, G: g, B: b, A: c.A}
+}
+
+// mapToGamut brings a linear sRGB color inside an RGB color space
+// Colors already inside the gamut are returned unchanged
+func mapToGamut(c LinearColor, space predefinedSpace, method GamutMapping) LinearColor {
+	if inGamut(c, space) {
+		return c
+	}
+
+	switch method {
+	case GamutMappingCSS4:
+		return css4GamutMap(c, space)
+	case GamutMappingChromaReduce:
+		return chromaReduceGamutMap(c, space)
+	default:
+		return clipToGamut(c, space)
+	}
+}
+
+// css4GamutMap implements the CSS Color Module Level 4 gamut mapping algorithm:
+// binary search on OKLCH chroma, accepting the clipped color as soon as it is
+// within a deltaEOK JND of the chroma-reduced one
+// See: https://www.w3.org/TR/css-color-4/#binsearch
+func css4GamutMap(c LinearColor, space predefinedSpace) LinearColor {
+	l, chroma, h := linearToOKLCH(c.R, c.G, c.B)
+
+	// Lightness outside 0-1 maps to white or black
+	if l >= OKLCH_L_Max {
+		return LinearColor{R: 1, G: 1, B: 1, A: c.A}
+	}
+	if l <= 0 {
+		return LinearColor{R: 0, G: 0, B: 0, A: c.A}
+	}
+
+	clipped := clipToGamut(c, space)
+	if deltaEOK(clipped, c) < gamutMappingJND {
+		return clipped
+	}
+
+	min, max := 0.0, chroma
+	minInGamut := true
+	for max-min > gamutMappingEpsilon {
+		mid := (min + max) / 2
+		r, g, b := oklchToLinear(l, mid, h)
+		current := LinearColor{R: r, G: g, B: b, A: c.A}
+
+		if minInGamut && inGamut(current, space) {
+			min = mid
+			continue
+		}
+
+		clipped = clipToGamut(current, space)
+		e := deltaEOK(clipped, current)
+		if e < gamutMappingJND {
+			if gamutMappingJND-e < gamutMappingEpsilon {
+				return clipped
+			}
+			minInGamut = false
+			min = mid
+		} else {
+			max = mid
+		}
+	}
+	return clipped
+}
+
+// chromaReduceGamutMap reduces OKLCH chroma at constant lightness and hue
+// until the color fits, then clips the remaining floating-point excess
+func chromaReduceGamutMap(c LinearColor, space predefinedSpace) LinearColor {
+	l, chroma, h := linearToOKLCH(c.R, c.G, c.B)
+
+	if l >= OKLCH_L_Max {
+		return LinearColor{R: 1, G: 1, B: 1, A: c.A}
+	}
+	if l <= 0 {
+		return LinearColor{R: 0, G: 0, B: 0, A: c.A}
+	}
+
+	min, max := 0.0, chroma
+	for max-min > gamutMappingEpsilon {
+		mid := (min + max) / 2
+		r, g, b := oklchToLinear(l, mid, h)
+		if inGamut(LinearColor{R: r, G: g, B: b}, space) {
+			min = mid
+		} else {
+			max = mid
+		}
+	}
+
+	r, g, b := oklchToLinear(l, min, h)
+	return clipToGamut(LinearColor{R: r, G: g, B: b, A: c.A}, space)
+}
+
+// deltaEOK calculates the Euclidean distance between two colors in OKLab
+func deltaEOK(c1, c2 LinearColor) float64 {
+	l1, a1, b1 := linearToOKLab(c1.R, c1.G, c1.B)
+	l2, a2, b2 := linearToOKLab(c2.R, c2.G, c2.B)
+
+	deltaL := l2 - l1
+	deltaA := a2 - a1
+	deltaB := b2 - b1
+
+	return math.Sqrt(deltaL*deltaL + deltaA*deltaA + deltaB*deltaB)
+}
+
+// GamutCheck describes whether a color fits inside one RGB gamut
+type GamutCheck struct {
+	Gamut     string      // human-readable gamut name
+	Space     ColorFormat // color() space that defines the gamut
+	InGamut   bool
+	Overshoot float64 // largest channel excursion outside 0-1 in the gamut's own space
+	Distance  float64 // ΔEOK to the nearest in-gamut color (0 when inside)
+	Nearest   string  // nearest in-gamut color in the gamut's own space
+}
+
+// GamutReport lists gamut membership of a color across common display gamuts
+type GamutReport struct {
+	Color   ColorData
+	Mapping GamutMapping
+	Checks  []GamutCheck
+}
+
+// checkedGamuts lists the gamuts reported by CheckGamut, from narrowest to widest
+var checkedGamuts = []struct {
+	name  string
+	space ColorFormat
+}{
+	{"sRGB", FormatSRGB},
+	{"Display P3", FormatDisplayP3},
+	{"Rec.2020", FormatRec2020},
+	{"ProPhoto RGB", FormatProPhotoRGB},
+}
+
+// CheckGamut reports whether a color lies inside sRGB, Display P3, Rec.2020 and
+// ProPhoto RGB, and finds the nearest in-gamut color with the given mapping
+func CheckGamut(color string, method GamutMapping) (*GamutReport, error) {
+	data, err := DetectFormat(color)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+
+	method, err = ParseGamutMapping(string(method))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GamutReport{Color: data, Mapping: method}
+	for _, gamut := range checkedGamuts {
+		space := predefinedSpaces[gamut.space]
+		nearest := mapToGamut(data.Linear, space, method)
+
+		check := GamutCheck{
+			Gamut:   gamut.name,
+			Space:   gamut.space,
+			InGamut: inGamut(data.Linear, space),
+			Nearest: formatPredefined(gamut.space, nearest),
+		}
+		if !check.InGamut {
+			c1, c2, c3 := linearToPredefined(space, data.Linear.R, data.Linear.G, data.Linear.B)
+			check.Overshoot = math.Max(channelOvershoot(c1), math.Max(channelOvershoot(c2), channelOvershoot(c3)))
+			check.Distance = deltaEOK(data.Linear, nearest)
+		}
+		if gamut.space == FormatSRGB {
+			c := nearest.SRGB()
+			check.Nearest = formatHEX(c.R, c.G, c.B, c.A)
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	return report, nil
+}
+
+// channelOvershoot returns how far a channel lies outside 0-1
+func channelOvershoot(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	if v > 1 {
+		return v - 1
+	}
+	return 0
+}
+
+// FormatGamutReport formats a gamut report as text
+func FormatGamutReport(report *GamutReport) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Gamut Check: %s (%s)\n", report.Color.Original, report.Color.Format))
+	builder.WriteString(fmt.Sprintf("Gamut mapping: %s\n\n", report.Mapping))
+
+	for _, check := range report.Checks {
+		if check.InGamut {
+			builder.WriteString(fmt.Sprintf("%s: inside\n", check.Gamut))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%s: outside (channel overshoot %.4f, %.4f ΔEOK to nearest)\n",
+			check.Gamut, check.Overshoot, check.Distance))
+		builder.WriteString(fmt.Sprintf("  Nearest in-gamut color: %s\n", check.Nearest))
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseGamutMapping(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  GamutMapping
+		expectErr bool
+	}{
+		{"", GamutMappingClip, false},
+		{"clip", GamutMappingClip, false},
+		{"CSS4", GamutMappingCSS4, false},
+		{"chroma-reduce", GamutMappingChromaReduce, false},
+		{"perceptual", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := ParseGamutMapping(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, m)
+			}
+		})
+	}
+}
+
+func TestGamutMappingPreservesHue(t *testing.T) {
+	srgb := predefinedSpaces[FormatSRGB]
+	inputs := []string{"oklch(0.7 0.3 150)", "oklch(0.6 0.35 30)", "color(display-p3 0 0 1)", "lab(50 120 -80)"}
+
+	for _, input := range inputs {
+		data, err := DetectFormat(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lOrig, _, hOrig := linearToOKLCH(data.Linear.R, data.Linear.G, data.Linear.B)
+
+		for _, method := range []GamutMapping{GamutMappingCSS4, GamutMappingChromaReduce} {
+			t.Run(input+"/"+string(method), func(t *testing.T) {
+				mapped := mapToGamut(data.Linear, srgb, method)
+				if !inGamut(mapped, srgb) {
+					t.Fatalf("mapped color is outside sRGB: %+v", mapped)
+				}
+				l, _, h := linearToOKLCH(mapped.R, mapped.G, mapped.B)
+				if calculateHueDifference(h, hOrig) > 3 {
+					t.Errorf("hue shifted from %.2f to %.2f", hOrig, h)
+				}
+				if math.Abs(l-lOrig) > 0.02 {
+					t.Errorf("lightness shifted from %.4f to %.4f", lOrig, l)
+				}
+			})
+		}
+	}
+}
+
+func TestGamutMappingInGamutUnchanged(t *testing.T) {
+	for _, method := range GetGamutMappings() {
+		result, err := ConvertWithOptions("#3B82F6", "hex", ConvertOptions{
+			PreserveAlpha: true,
+			GamutMapping:  GamutMapping(method),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != "#3B82F6" {
+			t.Errorf("%s: expected #3B82F6, got %s", method, result)
+		}
+	}
+}
+
+func TestGamutMappingConvert(t *testing.T) {
+	tests := []struct {
+		method   GamutMapping
+		input    string
+		expected string
+	}{
+		{GamutMappingClip, "oklch(0.7 0.3 150)", "#00CB00"},
+		{GamutMappingCSS4, "oklch(0.7 0.3 150)", "#00C248"},
+		{GamutMappingChromaReduce, "oklch(0.7 0.3 150)", "#00BE58"},
+		{GamutMappingCSS4, "oklch(1.2 0.1 150)", "#FFFFFF"},
+		{GamutMappingCSS4, "oklch(0 0.1 150)", "#000000"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.method)+"/"+tt.input, func(t *testing.T) {
+			result, err := ConvertWithOptions(tt.input, "hex", ConvertOptions{
+				PreserveAlpha: true,
+				GamutMapping:  tt.method,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := ConvertWithOptions("#FF0000", "hex", ConvertOptions{GamutMapping: "bogus"}); err == nil {
+		t.Error("expected error for unknown gamut mapping")
+	}
+}
+
+func TestCheckGamut(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]bool
+	}{
+		{"#FF0000", map[string]bool{"sRGB": true, "Display P3": true, "Rec.2020": true, "ProPhoto RGB": true}},
+		{"color(display-p3 0 1 0)", map[string]bool{"sRGB": false, "Display P3": true, "Rec.2020": true, "ProPhoto RGB": true}},
+		{"color(rec2020 0 1 0)", map[string]bool{"sRGB": false, "Display P3": false, "Rec.2020": true, "ProPhoto RGB": true}},
+		{"color(prophoto-rgb 0 0 1)", map[string]bool{"sRGB": false, "Display P3": false, "Rec.2020": false, "ProPhoto RGB": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			report, err := CheckGamut(tt.input, GamutMappingCSS4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Checks) != len(tt.expected) {
+				t.Fatalf("expected %d gamut checks, got %d", len(tt.expected), len(report.Checks))
+			}
+			for _, check := range report.Checks {
+				if check.InGamut != tt.expected[check.Gamut] {
+					t.Errorf("%s: expected in gamut %t, got %t", check.Gamut, tt.expected[check.Gamut], check.InGamut)
+				}
+				if check.InGamut && (check.Distance != 0 || check.Overshoot != 0) {
+					t.Errorf("%s: in-gamut color should have zero distance, got %f / %f", check.Gamut, check.Distance, check.Overshoot)
+				}
+				if !check.InGamut && check.Overshoot <= 0 {
+					t.Errorf("%s: out-of-gamut color should report an overshoot", check.Gamut)
+				}
+				nearest, err := DetectFormat(check.Nearest)
+				if err != nil {
+					t.Fatalf("%s: nearest color %q does not parse: %v", check.Gamut, check.Nearest, err)
+				}
+				if !inGamut(nearest.Linear, predefinedSpaces[check.Space]) {
+					t.Errorf("%s: nearest color %s is outside the gamut", check.Gamut, check.Nearest)
+				}
+			}
+
+			text := FormatGamutReport(report)
+			if !contains(text, "Gamut Check: "+tt.input) {
+				t.Errorf("report missing header: %s", text)
+			}
+		})
+	}
+
+	if _, err := CheckGamut("not-a-color", GamutMappingCSS4); err == nil {
+		t.Error("expected error for invalid color")
+	}
+}
@@ -0,0 +1,376 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Easing selects how gradient positions are distributed between the stops
+type Easing string
+
+const (
+	EasingLinear   Easing = "linear"       // evenly spaced
+	EasingIn       Easing = "ease-in"      // slow start (quadratic)
+	EasingOut      Easing = "ease-out"     // slow end (quadratic)
+	EasingInOut    Easing = "ease-in-out"  // slow start and end (smoothstep)
+	EasingSmoother Easing = "smootherstep" // flatter start and end than ease-in-out (quintic)
+)
+
+// GetEasings returns the supported easing functions
+func GetEasings() []string {
+	return []string{
+		string(EasingLinear),
+		string(EasingIn),
+		string(EasingOut),
+		string(EasingInOut),
+		string(EasingSmoother),
+	}
+}
+
+// ParseEasing validates an easing name; empty selects linear
+func ParseEasing(name string) (Easing, error) {
+	switch e := Easing(strings.ToLower(strings.TrimSpace(name))); e {
+	case "":
+		return EasingLinear, nil
+	case EasingLinear, EasingIn, EasingOut, EasingInOut, EasingSmoother:
+		return e, nil
+	default:
+		return "", fmt.Errorf("invalid easing: %s (supported: %s)", name, strings.Join(GetEasings(), ", "))
+	}
+}
+
+// apply maps a position in 0-1 to an eased position in 0-1
+func (e Easing) apply(t float64) float64 {
+	switch e {
+	case EasingIn:
+		return t * t
+	case EasingOut:
+		return 1 - (1-t)*(1-t)
+	case EasingInOut:
+		return t * t * (3 - 2*t)
+	case EasingSmoother:
+		return t * t * t * (t*(t*6-15) + 10)
+	default:
+		return t
+	}
+}
+
+// GradientType selects the CSS gradient function
+type GradientType string
+
+const (
+	GradientLinear GradientType = "linear"
+	GradientRadial GradientType = "radial"
+)
+
+// GetGradientTypes returns the supported CSS gradient functions
+func GetGradientTypes() []string {
+	return []string{string(GradientLinear), string(GradientRadial)}
+}
+
+// ParseGradientType validates a gradient type; empty selects linear
+func ParseGradientType(name string) (GradientType, error) {
+	switch g := GradientType(strings.ToLower(strings.TrimSpace(name))); g {
+	case "":
+		return GradientLinear, nil
+	case GradientLinear, GradientRadial:
+		return g, nil
+	default:
+		return "", fmt.Errorf("invalid gradient type: %s (supported: %s)", name, strings.Join(GetGradientTypes(), ", "))
+	}
+}
+
+var (
+	gradientAnglePattern  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)(deg|grad|rad|turn)$`)
+	gradientLengthPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)(%|px|em|rem|ex|ch|vw|vh|vmin|vmax|cm|mm|q|in|pt|pc)$`)
+)
+
+// parseGradientDirection validates the direction of a linear gradient (an
+// angle or "to <side>") or the shape, size and position of a radial gradient,
+// so it can be written into CSS as is. It returns the normalized direction;
+// empty selects "to right" or "circle".
+func parseGradientDirection(direction string, t GradientType) (string, error) {
+	words := strings.Fields(strings.ToLower(direction))
+	if len(words) == 0 {
+		if t == GradientRadial {
+			return "circle", nil
+		}
+		return "to right", nil
+	}
+	normalized := strings.Join(words, " ")
+
+	if t == GradientRadial {
+		at := len(words)
+		for i, w := range words {
+			if w == "at" {
+				at = i
+				break
+			}
+		}
+		if !validRadialShape(words[:at]) || (at < len(words) && !validGradientPosition(words[at+1:])) {
+			return "", fmt.Errorf("invalid radial gradient shape: %s (expected e.g. 'circle', 'ellipse closest-side' or 'circle at top left')", direction)
+		}
+		return normalized, nil
+	}
+
+	if len(words) == 1 && (words[0] == "0" || gradientAnglePattern.MatchString(words[0])) {
+		return normalized, nil
+	}
+	if words[0] == "to" && validGradientSide(words[1:]) {
+		return normalized, nil
+	}
+	return "", fmt.Errorf("invalid gradient direction: %s (expected an angle such as 45deg or a side such as 'to bottom right')", direction)
+}
+
+// validGradientSide accepts one or two sides, at most one horizontal and one vertical
+func validGradientSide(words []string) bool {
+	if len(words) == 0 || len(words) > 2 {
+		return false
+	}
+	horizontal, vertical := 0, 0
+	for _, w := range words {
+		switch w {
+		case "left", "right":
+			horizontal++
+		case "top", "bottom":
+			vertical++
+		default:
+			return false
+		}
+	}
+	return horizontal <= 1 && vertical <= 1
+}
+
+// validRadialShape accepts an optional circle/ellipse with an optional extent
+// keyword or one or two lengths, in any order
+func validRadialShape(words []string) bool {
+	shapes, extents, lengths := 0, 0, 0
+	for _, w := range words {
+		switch {
+		case w == "circle" || w == "ellipse":
+			shapes++
+		case w == "closest-side" || w == "closest-corner" || w == "farthest-side" || w == "farthest-corner":
+			extents++
+		case isGradientLength(w):
+			lengths++
+		default:
+			return false
+		}
+	}
+	return shapes <= 1 && extents+min(lengths, 1) <= 1 && lengths <= 2
+}
+
+// validGradientPosition accepts one to four position keywords or lengths
+func validGradientPosition(words []string) bool {
+	if len(words) == 0 || len(words) > 4 {
+		return false
+	}
+	for _, w := range words {
+		switch w {
+		case "left", "right", "top", "bottom", "center":
+		default:
+			if !isGradientLength(w) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isGradientLength reports whether w is a CSS length or percentage
+func isGradientLength(w string) bool {
+	return w == "0" || gradientLengthPattern.MatchString(w)
+}
+
+// Gradient defaults and limits
+const (
+	DefaultGradientSteps = 5
+	MaxGradientSteps     = 256
+
+	// gradientFallbackTolerance is the largest ΔEOK allowed between the true
+	// interpolation and the sRGB blend a browser draws between two fallback stops
+	gradientFallbackTolerance = 0.01
+	// gradientMaxSubdivision limits fallback stops to 2^n segments per color stop pair
+	gradientMaxSubdivision = 5
+)
+
+// GradientOptions controls how a gradient is sampled and written as CSS
+type GradientOptions struct {
+	Steps     int              // number of colors to sample, including both ends (default: 5)
+	Space     ColorFormat      // interpolation space (default: oklch)
+	Hue       HueInterpolation // hue interpolation for polar spaces (default: shorter)
+	Easing    Easing           // distribution of positions (default: linear)
+	Type      GradientType     // CSS gradient function (default: linear)
+	Direction string           // linear: angle or side, radial: shape/position (defaults: "to right", "circle")
+}
+
+// GradientColor is one sampled gradient color
+type GradientColor struct {
+	Position float64 // 0-1 along the gradient
+	HEX      string  // sRGB, gamut-mapped with the CSS Color 4 algorithm
+	OKLCH    string  // unmapped
+}
+
+// GradientResult holds sampled colors and CSS for a gradient
+type GradientResult struct {
+	Stops       []ColorData
+	Options     GradientOptions
+	Colors      []GradientColor
+	CSS         string // sRGB hex stops approximating the interpolation, for any browser
+	CSSNative   string // CSS Color 4 gradient interpolated by the browser in the chosen space
+	FallbackLen int    // number of stops in CSS
+}
+
+// GenerateGradient samples a gradient between two or more evenly spaced color stops
+// and writes it as linear-gradient() or radial-gradient() CSS
+func GenerateGradient(stops []string, opts GradientOptions) (*GradientResult, error) {
+	if len(stops) < 2 {
+		return nil, fmt.Errorf("at least two colors are required for a gradient")
+	}
+	if opts.Steps == 0 {
+		opts.Steps = DefaultGradientSteps
+	}
+	if opts.Steps < 2 || opts.Steps > MaxGradientSteps {
+		return nil, fmt.Errorf("steps must be between 2 and %d, got %d", MaxGradientSteps, opts.Steps)
+	}
+
+	var err error
+	if opts.Space == "" {
+		opts.Space = FormatOKLCH
+	}
+	if opts.Space, err = ParseInterpolationSpace(string(opts.Space)); err != nil {
+		return nil, err
+	}
+	if opts.Hue, err = ParseHueInterpolation(string(opts.Hue)); err != nil {
+		return nil, err
+	}
+	if opts.Easing, err = ParseEasing(string(opts.Easing)); err != nil {
+		return nil, err
+	}
+	if opts.Type, err = ParseGradientType(string(opts.Type)); err != nil {
+		return nil, err
+	}
+	if opts.Direction, err = parseGradientDirection(opts.Direction, opts.Type); err != nil {
+		return nil, err
+	}
+
+	result := &GradientResult{Options: opts}
+	linear := make([]LinearColor, len(stops))
+	for i, stop := range stops {
+		data, err := DetectFormat(stop)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q: %w", stop, err)
+		}
+		result.Stops = append(result.Stops, data)
+		linear[i] = data.Linear
+	}
+
+	colorAt := func(pos float64) LinearColor {
+		return interpolateStops(linear, opts.Easing.apply(pos), opts.Space, opts.Hue)
+	}
+
+	for i := 0; i < opts.Steps; i++ {
+		pos := float64(i) / float64(opts.Steps-1)
+		lc := colorAt(pos)
+		result.Colors = append(result.Colors, GradientColor{
+			Position: pos,
+			HEX:      gradientHEX(lc),
+			OKLCH:    formatOKLCH(lc),
+		})
+	}
+
+	// Fallback stops: subdivide each segment until the sRGB blend is close enough
+	positions := []float64{0}
+	segments := len(stops) - 1
+	for s := 0; s < segments; s++ {
+		start := float64(s) / float64(segments)
+		end := float64(s+1) / float64(segments)
+		positions = append(positions, subdivideGradient(colorAt, start, end, 0)...)
+	}
+	fallback := make([]string, len(positions))
+	for i, pos := range positions {
+		fallback[i] = fmt.Sprintf("%s %s", gradientHEX(colorAt(pos)), formatGradientPercent(pos))
+	}
+
+	// Without easing the browser can interpolate the original stops itself; eased
+	// gradients reuse the fallback positions, which are close enough for the shorter hue
+	function := string(opts.Type) + "-gradient"
+	interpolation := "in " + string(opts.Space)
+	var native []string
+	if opts.Easing == EasingLinear {
+		if interpolationSpaces[opts.Space].hue >= 0 && opts.Hue != HueShorter {
+			interpolation += " " + string(opts.Hue) + " hue"
+		}
+		for _, stop := range result.Stops {
+			native = append(native, stop.Original)
+		}
+	} else {
+		for _, pos := range positions {
+			native = append(native, fmt.Sprintf("%s %s", formatOKLCH(colorAt(pos)), formatGradientPercent(pos)))
+		}
+	}
+	result.CSS = fmt.Sprintf("%s(%s, %s)", function, opts.Direction, strings.Join(fallback, ", "))
+	result.CSSNative = fmt.Sprintf("%s(%s %s, %s)", function, opts.Direction, interpolation, strings.Join(native, ", "))
+	result.FallbackLen = len(positions)
+
+	return result, nil
+}
+
+// subdivideGradient returns the fallback stop positions in (start, end], adding
+// midpoints while the browser's sRGB blend strays too far from the true color
+func subdivideGradient(colorAt func(float64) LinearColor, start, end float64, depth int) []float64 {
+	mid := (start + end) / 2
+	want := sRGBGamutMap(colorAt(mid))
+	blend := mixColors(sRGBGamutMap(colorAt(start)), sRGBGamutMap(colorAt(end)), 0.5, FormatSRGB, HueShorter)
+
+	if depth >= gradientMaxSubdivision || deltaEOK(want, blend) <= gradientFallbackTolerance {
+		return []float64{end}
+	}
+	left := subdivideGradient(colorAt, start, mid, depth+1)
+	right := subdivideGradient(colorAt, mid, end, depth+1)
+	return append(left, right...)
+}
+
+// sRGBGamutMap brings a color inside sRGB with the CSS Color 4 algorithm
+func sRGBGamutMap(lc LinearColor) LinearColor {
+	return mapToGamut(lc, predefinedSpaces[FormatSRGB], GamutMappingCSS4)
+}
+
+// gradientHEX formats a gradient color as hex, gamut-mapped into sRGB
+func gradientHEX(lc LinearColor) string {
+	c := sRGBGamutMap(lc).SRGB()
+	return formatHEX(c.R, c.G, c.B, c.A)
+}
+
+// formatGradientPercent formats a 0-1 position as a CSS percentage
+func formatGradientPercent(pos float64) string {
+	return strconv.FormatFloat(math.Round(pos*10000)/100, 'f', -1, 64) + "%"
+}
+
+// FormatGradient formats a gradient as text
+func FormatGradient(result *GradientResult) string {
+	var builder strings.Builder
+	stops := make([]string, len(result.Stops))
+	for i, stop := range result.Stops {
+		stops[i] = stop.Original
+	}
+	builder.WriteString(fmt.Sprintf("Gradient: %s\n", strings.Join(stops, " → ")))
+	builder.WriteString(fmt.Sprintf("Space: %s", result.Options.Space))
+	if interpolationSpaces[result.Options.Space].hue >= 0 {
+		builder.WriteString(fmt.Sprintf(" (%s hue)", result.Options.Hue))
+	}
+	builder.WriteString(fmt.Sprintf(", easing: %s\n\n", result.Options.Easing))
+
+	builder.WriteString("Colors:\n")
+	for _, c := range result.Colors {
+		builder.WriteString(fmt.Sprintf("  %.3f: %s  %s\n", c.Position, c.HEX, c.OKLCH))
+	}
+
+	builder.WriteString(fmt.Sprintf("\nCSS (sRGB, %d stops):\n%s\n", result.FallbackLen, result.CSS))
+	builder.WriteString(fmt.Sprintf("\nCSS (native %s interpolation):\n%s", result.Options.Space, result.CSSNative))
+
+	return builder.String()
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateGradient(t *testing.T) {
+	result, err := GenerateGradient([]string{"#FF0000", "blue"}, GradientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHEX := []string{"#FF0000", "#E4007B", "#B700BE", "#7A00F1", "#0000FF"}
+	if len(result.Colors) != len(expectedHEX) {
+		t.Fatalf("expected %d colors, got %d", len(expectedHEX), len(result.Colors))
+	}
+	for i, c := range result.Colors {
+		if c.HEX != expectedHEX[i] {
+			t.Errorf("color %d: expected %s, got %s", i, expectedHEX[i], c.HEX)
+		}
+	}
+	if result.Colors[2].OKLCH != "oklch(0.5400 0.2854 326.64)" {
+		t.Errorf("unexpected midpoint %s", result.Colors[2].OKLCH)
+	}
+
+	if !strings.HasPrefix(result.CSS, "linear-gradient(to right, #FF0000 0%, ") || !strings.HasSuffix(result.CSS, "#0000FF 100%)") {
+		t.Errorf("unexpected CSS: %s", result.CSS)
+	}
+	// sRGB interpolation between red and blue is far from OKLCH, so extra stops are needed
+	if result.FallbackLen <= 2 {
+		t.Errorf("expected extra fallback stops, got %d", result.FallbackLen)
+	}
+	if result.CSSNative != "linear-gradient(to right in oklch, #FF0000, blue)" {
+		t.Errorf("unexpected native CSS: %s", result.CSSNative)
+	}
+}
+
+func TestGenerateGradientOptions(t *testing.T) {
+	t.Run("srgb needs no extra stops", func(t *testing.T) {
+		result, err := GenerateGradient([]string{"red", "blue"}, GradientOptions{Space: FormatSRGB})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.CSS != "linear-gradient(to right, #FF0000 0%, #0000FF 100%)" {
+			t.Errorf("unexpected CSS: %s", result.CSS)
+		}
+	})
+
+	t.Run("radial longer hue", func(t *testing.T) {
+		result, err := GenerateGradient([]string{"red", "blue"}, GradientOptions{Type: GradientRadial, Hue: HueLonger})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(result.CSS, "radial-gradient(circle, ") {
+			t.Errorf("unexpected CSS: %s", result.CSS)
+		}
+		if result.CSSNative != "radial-gradient(circle in oklch longer hue, red, blue)" {
+			t.Errorf("unexpected native CSS: %s", result.CSSNative)
+		}
+		if result.Colors[2].HEX != "#008A0E" {
+			t.Errorf("expected the longer arc through green, got %s", result.Colors[2].HEX)
+		}
+	})
+
+	t.Run("eased", func(t *testing.T) {
+		result, err := GenerateGradient([]string{"black", "white"}, GradientOptions{Steps: 3, Easing: EasingIn, Space: FormatSRGB, Direction: "to bottom"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Colors[1].HEX != "#404040" {
+			t.Errorf("expected ease-in midpoint #404040, got %s", result.Colors[1].HEX)
+		}
+		if !strings.HasPrefix(result.CSSNative, "linear-gradient(to bottom in srgb, oklch(") {
+			t.Errorf("unexpected native CSS: %s", result.CSSNative)
+		}
+	})
+}
+
+func TestGenerateGradientErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		stops []string
+		opts  GradientOptions
+	}{
+		{"single stop", []string{"red"}, GradientOptions{}},
+		{"bad color", []string{"red", "nope"}, GradientOptions{}},
+		{"too few steps", []string{"red", "blue"}, GradientOptions{Steps: 1}},
+		{"bad easing", []string{"red", "blue"}, GradientOptions{Easing: "bounce"}},
+		{"bad type", []string{"red", "blue"}, GradientOptions{Type: "conic"}},
+		{"injected direction", []string{"red", "blue"}, GradientOptions{Direction: "foo); background: url(x"}},
+		{"injected radial shape", []string{"red", "blue"}, GradientOptions{Type: GradientRadial, Direction: "circle at top; color: red"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateGradient(tt.stops, tt.opts); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseGradientDirection(t *testing.T) {
+	tests := []struct {
+		direction string
+		gradient  GradientType
+		expected  string // empty when invalid
+	}{
+		{"", GradientLinear, "to right"},
+		{"", GradientRadial, "circle"},
+		{"45deg", GradientLinear, "45deg"},
+		{"-0.25TURN", GradientLinear, "-0.25turn"},
+		{"0", GradientLinear, "0"},
+		{"  to   Bottom  right ", GradientLinear, "to bottom right"},
+		{"ellipse at top", GradientRadial, "ellipse at top"},
+		{"closest-side circle at 25% 75%", GradientRadial, "closest-side circle at 25% 75%"},
+		{"ellipse 40px 20px", GradientRadial, "ellipse 40px 20px"},
+		{"at center", GradientRadial, "at center"},
+		{"45", GradientLinear, ""},
+		{"to left right", GradientLinear, ""},
+		{"to", GradientLinear, ""},
+		{"circle", GradientLinear, ""},
+		{"to bottom", GradientRadial, ""},
+		{"circle ellipse", GradientRadial, ""},
+		{"circle closest-side 10px", GradientRadial, ""},
+		{"circle at", GradientRadial, ""},
+		{"foo); background: url(x", GradientLinear, ""},
+	}
+	for _, tt := range tests {
+		got, err := parseGradientDirection(tt.direction, tt.gradient)
+		if tt.expected == "" {
+			if err == nil {
+				t.Errorf("%s %q: expected error, got %q", tt.gradient, tt.direction, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("%s %q: expected %q, got %q (%v)", tt.gradient, tt.direction, tt.expected, got, err)
+		}
+	}
+}
+
+func TestEasing(t *testing.T) {
+	for _, name := range GetEasings() {
+		e := Easing(name)
+		if e.apply(0) != 0 || e.apply(1) != 1 {
+			t.Errorf("%s does not map 0 and 1 onto themselves", name)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// HarmonyType names a color harmony scheme
+type HarmonyType string
+
+const (
+	HarmonyComplementary      HarmonyType = "complementary"
+	HarmonySplitComplementary HarmonyType = "split-complementary"
+	HarmonyAnalogous          HarmonyType = "analogous"
+	HarmonyTriadic            HarmonyType = "triadic"
+	HarmonyTetradic           HarmonyType = "tetradic" // rectangle: two complementary pairs 60° apart
+	HarmonySquare             HarmonyType = "square"
+	HarmonyMonochromatic      HarmonyType = "monochromatic"
+)
+
+// harmonyHueOffsets holds the OKLCH hue rotations of each scheme, base first
+var harmonyHueOffsets = map[HarmonyType][]float64{
+	HarmonyComplementary:      {0, 180},
+	HarmonySplitComplementary: {0, 150, 210},
+	HarmonyAnalogous:          {0, -30, 30},
+	HarmonyTriadic:            {0, 120, 240},
+	HarmonyTetradic:           {0, 60, 180, 240},
+	HarmonySquare:             {0, 90, 180, 270},
+}
+
+// Monochromatic schemes spread lightness evenly over this OKLCH range
+const (
+	monochromaticSteps    = 5
+	monochromaticMinLight = 0.25
+	monochromaticMaxLight = 0.95
+)
+
+// GetHarmonyTypes returns the supported harmony schemes
+func GetHarmonyTypes() []string {
+	return []string{
+		string(HarmonyComplementary),
+		string(HarmonySplitComplementary),
+		string(HarmonyAnalogous),
+		string(HarmonyTriadic),
+		string(HarmonyTetradic),
+		string(HarmonySquare),
+		string(HarmonyMonochromatic),
+	}
+}
+
+// ParseHarmonyType validates a harmony scheme name
+func ParseHarmonyType(name string) (HarmonyType, error) {
+	h := HarmonyType(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := harmonyHueOffsets[h]; ok || h == HarmonyMonochromatic {
+		return h, nil
+	}
+	return "", fmt.Errorf("invalid harmony: %s (supported: %s)", name, strings.Join(GetHarmonyTypes(), ", "))
+}
+
+// HarmonyColor is one color of a harmony scheme
+type HarmonyColor struct {
+	Color         string  // in the requested format
+	HEX           string  // sRGB hex of the same color
+	Hue           float64 // OKLCH hue
+	Lightness     float64 // OKLCH lightness
+	ContrastWhite float64 // WCAG contrast ratio against #FFFFFF
+	ContrastBlack float64 // WCAG contrast ratio against #000000
+}
+
+// HarmonyResult holds a harmony scheme built from a base color
+type HarmonyResult struct {
+	Base    ColorData
+	Harmony HarmonyType
+	Format  string
+	Colors  []HarmonyColor
+}
+
+// GenerateHarmony builds a harmony scheme from a base color by rotating its hue
+// in OKLCH, so lightness stays even across the scheme; monochromatic schemes vary
+// lightness instead. Each color is gamut-mapped into sRGB.
+func GenerateHarmony(color string, harmony HarmonyType, targetFormat string, opts ConvertOptions) (*HarmonyResult, error) {
+	data, err := DetectFormat(color)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+	harmony, err = ParseHarmonyType(string(harmony))
+	if err != nil {
+		return nil, err
+	}
+
+	l, c, h := linearToOKLCH(data.Linear.R, data.Linear.G, data.Linear.B)
+
+	var coords [][3]float64
+	if harmony == HarmonyMonochromatic {
+		for _, light := range monochromaticLightness(l) {
+			coords = append(coords, [3]float64{light, c, h})
+		}
+	} else {
+		for _, offset := range harmonyHueOffsets[harmony] {
+			coords = append(coords, [3]float64{l, c, normalizeHue(h + offset)})
+		}
+	}
+
+	result := &HarmonyResult{Base: data, Harmony: harmony, Format: targetFormat}
+	white := Color{R: RGBMax, G: RGBMax, B: RGBMax, A: AlphaMax}
+	black := Color{A: AlphaMax}
+	for _, p := range coords {
+		r, g, b := oklchToLinear(p[0], p[1], p[2])
+		lc := sRGBGamutMap(LinearColor{R: r, G: g, B: b, A: data.Linear.A})
+		srgb := lc.SRGB()
+
+		formatted, err := FormatColor(ColorData{Color: srgb, Linear: lc}, targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Colors = append(result.Colors, HarmonyColor{
+			Color:         formatted,
+			HEX:           formatHEX(srgb.R, srgb.G, srgb.B, srgb.A),
+			Hue:           p[2],
+			Lightness:     p[0],
+			ContrastWhite: calculateContrastRatio(srgb, white),
+			ContrastBlack: calculateContrastRatio(srgb, black),
+		})
+	}
+
+	return result, nil
+}
+
+// monochromaticLightness spreads lightness values evenly and moves the one
+// closest to the base lightness onto it
+func monochromaticLightness(base float64) []float64 {
+	values := make([]float64, monochromaticSteps)
+	closest := 0
+	for i := range values {
+		values[i] = monochromaticMinLight + (monochromaticMaxLight-monochromaticMinLight)*float64(i)/float64(monochromaticSteps-1)
+		if math.Abs(values[i]-base) < math.Abs(values[closest]-base) {
+			closest = i
+		}
+	}
+	values[closest] = base
+	return values
+}
+
+// FormatHarmony formats a harmony scheme as text
+func FormatHarmony(result *HarmonyResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Harmony: %s from %s (%s)\n", result.Harmony, result.Base.Original, result.Base.Format))
+	if result.Harmony == HarmonyMonochromatic {
+		builder.WriteString("Lightness varied in OKLCH, gamut-mapped to sRGB\n\n")
+	} else {
+		builder.WriteString("Hue rotated in OKLCH, gamut-mapped to sRGB\n\n")
+	}
+
+	for i, c := range result.Colors {
+		if c.Color == c.HEX {
+			builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, c.Color))
+		} else {
+			builder.WriteString(fmt.Sprintf("%d. %s (%s)\n", i+1, c.Color, c.HEX))
+		}
+		builder.WriteString(fmt.Sprintf("   OKLCH lightness %.3f, hue %.1f°\n", c.Lightness, c.Hue))
+		builder.WriteString(fmt.Sprintf("   Contrast vs white: %.2f:1 (%s)\n", c.ContrastWhite, getWCAGGrade(c.ContrastWhite)))
+		builder.WriteString(fmt.Sprintf("   Contrast vs black: %.2f:1 (%s)\n", c.ContrastBlack, getWCAGGrade(c.ContrastBlack)))
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
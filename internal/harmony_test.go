@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGenerateHarmony(t *testing.T) {
+	tests := []struct {
+		harmony HarmonyType
+		hues    []float64 // rotation from the base hue
+	}{
+		{HarmonyComplementary, []float64{0, 180}},
+		{HarmonySplitComplementary, []float64{0, 150, 210}},
+		{HarmonyAnalogous, []float64{0, -30, 30}},
+		{HarmonyTriadic, []float64{0, 120, 240}},
+		{HarmonyTetradic, []float64{0, 60, 180, 240}},
+		{HarmonySquare, []float64{0, 90, 180, 270}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.harmony), func(t *testing.T) {
+			result, err := GenerateHarmony("#3b82f6", tt.harmony, "hex", ConvertOptions{PreserveAlpha: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Colors) != len(tt.hues) {
+				t.Fatalf("expected %d colors, got %d", len(tt.hues), len(result.Colors))
+			}
+			if result.Colors[0].Color != "#3B82F6" {
+				t.Errorf("expected the base color first, got %s", result.Colors[0].Color)
+			}
+			baseHue := result.Colors[0].Hue
+			for i, c := range result.Colors {
+				if diff := calculateHueDifference(c.Hue, normalizeHue(baseHue+tt.hues[i])); diff > 1e-9 {
+					t.Errorf("color %d: hue %.2f is %.2f° off", i, c.Hue, diff)
+				}
+				// Lightness stays even because the hue is rotated in OKLCH
+				data, err := DetectFormat(c.HEX)
+				if err != nil {
+					t.Fatal(err)
+				}
+				l, _, _ := rgbToOKLCH(data.Color.R, data.Color.G, data.Color.B)
+				if math.Abs(l-result.Colors[0].Lightness) > 0.01 {
+					t.Errorf("color %d (%s): lightness %.3f differs from base %.3f", i, c.HEX, l, result.Colors[0].Lightness)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateHarmonyTriadicColors(t *testing.T) {
+	result, err := GenerateHarmony("#3b82f6", HarmonyTriadic, "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"#3B82F6", "#E24956", "#3BA01B"}
+	for i, c := range result.Colors {
+		if c.Color != expected[i] {
+			t.Errorf("color %d: expected %s, got %s", i, expected[i], c.Color)
+		}
+	}
+	if math.Abs(result.Colors[0].ContrastWhite-3.68) > 0.01 || math.Abs(result.Colors[0].ContrastBlack-5.71) > 0.01 {
+		t.Errorf("unexpected contrast: %.2f vs white, %.2f vs black", result.Colors[0].ContrastWhite, result.Colors[0].ContrastBlack)
+	}
+}
+
+func TestGenerateHarmonyMonochromatic(t *testing.T) {
+	result, err := GenerateHarmony("#3b82f6", HarmonyMonochromatic, "oklch", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Colors) != monochromaticSteps {
+		t.Fatalf("expected %d colors, got %d", monochromaticSteps, len(result.Colors))
+	}
+	for i := 1; i < len(result.Colors); i++ {
+		if result.Colors[i].Lightness <= result.Colors[i-1].Lightness {
+			t.Errorf("lightness is not increasing at %d", i)
+		}
+	}
+	if result.Colors[2].HEX != "#3B82F6" {
+		t.Errorf("expected the base at the middle step, got %s", result.Colors[2].HEX)
+	}
+}
+
+func TestGenerateHarmonyErrors(t *testing.T) {
+	if _, err := GenerateHarmony("#3b82f6", "pentadic", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for unknown harmony")
+	}
+	if _, err := GenerateHarmony("nope", HarmonyTriadic, "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for invalid color")
+	}
+	if _, err := GenerateHarmony("#3b82f6", HarmonyTriadic, "pantone", ConvertOptions{}); err == nil {
+		t.Error("expected error for invalid target format")
+	}
+}
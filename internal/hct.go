@@ -0,0 +1,292 @@
+package internal
+
+import (
+	"math"
+)
+
+// HCT (hue, chroma, tone) is the color space of Material Design 3: hue and
+// chroma come from CAM16 under Material's default viewing conditions and tone
+// is CIE L*, so tone differences map directly to contrast
+
+// cam16Matrix converts XYZ to the CAM16 cone space
+var cam16Matrix = [3][3]float64{
+	{0.401288, 0.650173, -0.051461},
+	{-0.250268, 1.204414, 0.045854},
+	{-0.002079, 0.048952, 0.953127},
+}
+
+var cam16InverseMatrix = invertMatrix3(cam16Matrix)
+
+// cam16ViewingConditions holds the values CAM16 derives from the viewing conditions
+type cam16ViewingConditions struct {
+	n, aw, nbb, ncb, c, nc, fl, z float64
+	rgbD                          [3]float64
+}
+
+// cam16Default are Material's viewing conditions: a D65 white, an adapting
+// luminance of 200/π · Y(L* 50), an L* 50 background and an average surround
+var cam16Default = newCAM16ViewingConditions(
+	illuminantWhites[IlluminantD65],
+	200/math.Pi*yFromLstar(50)/100,
+	50,
+	2,
+)
+
+// newCAM16ViewingConditions precomputes CAM16 parameters for a white point
+// (Y = 1), adapting luminance in cd/m², background L* and surround (0 dark - 2 average)
+func newCAM16ViewingConditions(white [3]float64, adaptingLuminance, backgroundLstar, surround float64) cam16ViewingConditions {
+	var vc cam16ViewingConditions
+	rW, gW, bW := mulMatrix3(cam16Matrix, white[0]*100, white[1]*100, white[2]*100)
+
+	f := 0.8 + surround/10
+	if f >= 0.9 {
+		vc.c = lerp(0.59, 0.69, (f-0.9)*10)
+	} else {
+		vc.c = lerp(0.525, 0.59, (f-0.8)*10)
+	}
+	vc.nc = f
+
+	d := clamp(f*(1-(1/3.6)*math.Exp((-adaptingLuminance-42)/92)), 0, 1)
+	vc.rgbD = [3]float64{d*100/rW + 1 - d, d*100/gW + 1 - d, d*100/bW + 1 - d}
+
+	k := 1 / (5*adaptingLuminance + 1)
+	k4 := k * k * k * k
+	k4F := 1 - k4
+	vc.fl = k4*adaptingLuminance + 0.1*k4F*k4F*math.Cbrt(5*adaptingLuminance)
+
+	vc.n = yFromLstar(backgroundLstar) / (white[1] * 100)
+	vc.z = 1.48 + math.Sqrt(vc.n)
+	vc.nbb = 0.725 / math.Pow(vc.n, 0.2)
+	vc.ncb = vc.nbb
+
+	rA := cam16Adapt(vc.fl, vc.rgbD[0]*rW)
+	gA := cam16Adapt(vc.fl, vc.rgbD[1]*gW)
+	bA := cam16Adapt(vc.fl, vc.rgbD[2]*bW)
+	vc.aw = (2*rA + gA + 0.05*bA) * vc.nbb
+	return vc
+}
+
+// cam16Adapt applies the post-adaptation nonlinear compression to a cone response
+func cam16Adapt(fl, v float64) float64 {
+	af := math.Pow(fl*math.Abs(v)/100, 0.42)
+	return math.Copysign(400*af/(af+27.13), v)
+}
+
+// cam16Unadapt inverts cam16Adapt
+func cam16Unadapt(fl, v float64) float64 {
+	base := math.Max(0, 27.13*math.Abs(v)/(400-math.Abs(v)))
+	return math.Copysign(100/fl*math.Pow(base, 1/0.42), v)
+}
+
+// linearToCAM16 converts linear sRGB to CAM16 lightness J, chroma and hue
+func linearToCAM16(r, g, b float64, vc cam16ViewingConditions) (j, c, h float64) {
+	x, y, z := linearToXYZ(r, g, b)
+	rC, gC, bC := mulMatrix3(cam16Matrix, x*100, y*100, z*100)
+
+	rA := cam16Adapt(vc.fl, vc.rgbD[0]*rC)
+	gA := cam16Adapt(vc.fl, vc.rgbD[1]*gC)
+	bA := cam16Adapt(vc.fl, vc.rgbD[2]*bC)
+
+	a := (11*rA - 12*gA + bA) / 11
+	bb := (rA + gA - 2*bA) / 9
+	u := (20*rA + 20*gA + 21*bA) / 20
+	p2 := (40*rA + 20*gA + bA) / 20
+
+	h = normalizeHue(math.Atan2(bb, a) * 180 / math.Pi)
+	ac := p2 * vc.nbb
+	j = 100 * math.Pow(math.Max(0, ac/vc.aw), vc.c*vc.z)
+
+	eHue := 0.25 * (math.Cos(h*math.Pi/180+2) + 3.8)
+	p1 := 50000.0 / 13 * eHue * vc.nc * vc.ncb
+	t := p1 * math.Hypot(a, bb) / (u + 0.305)
+	alpha := math.Pow(t, 0.9) * math.Pow(1.64-math.Pow(0.29, vc.n), 0.73)
+	c = alpha * math.Sqrt(j/100)
+
+	return j, c, h
+}
+
+// cam16ToLinear converts CAM16 lightness J, chroma and hue to linear sRGB
+func cam16ToLinear(j, c, h float64, vc cam16ViewingConditions) (r, g, b float64) {
+	alpha := 0.0
+	if c != 0 && j != 0 {
+		alpha = c / math.Sqrt(j/100)
+	}
+	t := math.Pow(alpha/math.Pow(1.64-math.Pow(0.29, vc.n), 0.73), 1/0.9)
+	hRad := h * math.Pi / 180
+
+	eHue := 0.25 * (math.Cos(hRad+2) + 3.8)
+	ac := vc.aw * math.Pow(j/100, 1/vc.c/vc.z)
+	p1 := eHue * (50000.0 / 13) * vc.nc * vc.ncb
+	p2 := ac / vc.nbb
+
+	hSin, hCos := math.Sin(hRad), math.Cos(hRad)
+	gamma := 23 * (p2 + 0.305) * t / (23*p1 + 11*t*hCos + 108*t*hSin)
+	a := gamma * hCos
+	bb := gamma * hSin
+
+	rA := (460*p2 + 451*a + 288*bb) / 1403
+	gA := (460*p2 - 891*a - 261*bb) / 1403
+	bA := (460*p2 - 220*a - 6300*bb) / 1403
+
+	rF := cam16Unadapt(vc.fl, rA) / vc.rgbD[0]
+	gF := cam16Unadapt(vc.fl, gA) / vc.rgbD[1]
+	bF := cam16Unadapt(vc.fl, bA) / vc.rgbD[2]
+
+	x, y, z := mulMatrix3(cam16InverseMatrix, rF, gF, bF)
+	return xyzToLinear(x/100, y/100, z/100)
+}
+
+// linearToHCT converts linear sRGB to HCT
+// Returns h: 0-360, c: 0-~145, t: 0-100
+func linearToHCT(r, g, b float64) (h, c, t float64) {
+	_, c, h = linearToCAM16(r, g, b, cam16Default)
+	_, y, _ := linearToXYZ(r, g, b)
+	return h, c, lstarFromY(y * 100)
+}
+
+// hctToLinear converts HCT to linear sRGB. Chroma beyond what sRGB can show at
+// the given hue and tone is reduced to the largest in-gamut chroma, as Material
+// does, so hue and tone are kept.
+func hctToLinear(h, c, t float64) (r, g, b float64) {
+	t = clamp(t, 0, HCT_T_Max)
+	if c < 1e-4 || t < 1e-4 || t > HCT_T_Max-1e-4 {
+		y := yFromLstar(t) / 100
+		return y, y, y
+	}
+	h = normalizeHue(h)
+
+	if r, g, b, ok := hctSolve(h, c, t); ok {
+		return clamp(r, 0, 1), clamp(g, 0, 1), clamp(b, 0, 1)
+	}
+
+	return hctGamutLimit(h, yFromLstar(t)/100)
+}
+
+// hctGamutLimit returns the most chromatic sRGB color with the given CAM16 hue
+// and luminance (0-1), on the edge of the gamut slice at that luminance. Like
+// Material's HctSolver it brackets the hue between two vertices of the slice
+// and bisects the edge between them; where the slice does not reach the hue,
+// as next to white and black, that lands on the closest color Material picks.
+func hctGamutLimit(h, y float64) (r, g, b float64) {
+	left, right := hctSegment(y, h)
+	leftHue := cam16Hue(left)
+	for i := 0; i < 40; i++ {
+		mid := [3]float64{(left[0] + right[0]) / 2, (left[1] + right[1]) / 2, (left[2] + right[2]) / 2}
+		midHue := cam16Hue(mid)
+		if inCyclicOrder(leftHue, h, midHue) {
+			right = mid
+		} else {
+			left, leftHue = mid, midHue
+		}
+	}
+	return clamp((left[0]+right[0])/2, 0, 1), clamp((left[1]+right[1])/2, 0, 1), clamp((left[2]+right[2])/2, 0, 1)
+}
+
+// hctSegment finds the two vertices of the gamut slice at luminance y whose
+// hues bracket h, walking the 12 edges of the RGB cube in Material's order
+func hctSegment(y, h float64) (left, right [3]float64) {
+	leftHue, rightHue := 0.0, 0.0
+	initialized, uncut := false, true
+	for n := 0; n < 12; n++ {
+		mid, ok := hctVertex(y, n)
+		if !ok {
+			continue
+		}
+		midHue := cam16Hue(mid)
+		if !initialized {
+			left, right, leftHue, rightHue = mid, mid, midHue, midHue
+			initialized = true
+			continue
+		}
+		if uncut || inCyclicOrder(leftHue, midHue, rightHue) {
+			uncut = false
+			if inCyclicOrder(leftHue, h, midHue) {
+				right, rightHue = mid, midHue
+			} else {
+				left, leftHue = mid, midHue
+			}
+		}
+	}
+	return left, right
+}
+
+// hctVertex returns where the nth edge of the RGB cube crosses luminance y
+func hctVertex(y float64, n int) ([3]float64, bool) {
+	lum := srgbToXYZMatrix[1]
+	coordA := 0.0
+	if n%4 > 1 {
+		coordA = 1
+	}
+	coordB := 0.0
+	if n%2 == 1 {
+		coordB = 1
+	}
+
+	var v [3]float64
+	switch {
+	case n < 4:
+		v = [3]float64{(y - coordA*lum[1] - coordB*lum[2]) / lum[0], coordA, coordB}
+	case n < 8:
+		v = [3]float64{coordB, (y - coordB*lum[0] - coordA*lum[2]) / lum[1], coordA}
+	default:
+		v = [3]float64{coordA, coordB, (y - coordA*lum[0] - coordB*lum[1]) / lum[2]}
+	}
+	for _, c := range v {
+		if c < 0 || c > 1 {
+			return v, false
+		}
+	}
+	return v, true
+}
+
+// cam16Hue returns the CAM16 hue of a linear sRGB color
+func cam16Hue(v [3]float64) float64 {
+	_, _, h := linearToCAM16(v[0], v[1], v[2], cam16Default)
+	return h
+}
+
+// inCyclicOrder reports whether hues a, b and c appear in that order going
+// counterclockwise from a
+func inCyclicOrder(a, b, c float64) bool {
+	return normalizeHue(b-a) < normalizeHue(c-a)
+}
+
+// hctSolve finds the CAM16 lightness J whose color at the given hue and chroma
+// has the requested tone, reporting whether that color lies inside sRGB
+func hctSolve(h, c, t float64) (r, g, b float64, ok bool) {
+	targetY := yFromLstar(t) / 100
+	lo, hi := 0.0, 100.0
+	for i := 0; i < 50; i++ {
+		j := (lo + hi) / 2
+		r, g, b = cam16ToLinear(j, c, h, cam16Default)
+		_, y, _ := linearToXYZ(r, g, b)
+		if math.IsNaN(y) || y < targetY {
+			lo = j
+		} else {
+			hi = j
+		}
+	}
+	r, g, b = cam16ToLinear((lo+hi)/2, c, h, cam16Default)
+
+	const eps = 1e-4
+	ok = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	_, y, _ := linearToXYZ(r, g, b)
+	ok = ok && math.Abs(lstarFromY(y*100)-t) < 0.01
+	return r, g, b, ok
+}
+
+// lstarFromY converts relative luminance (0-100) to CIE L*
+func lstarFromY(y float64) float64 {
+	return 116*labF(y/100) - 16
+}
+
+// yFromLstar converts CIE L* to relative luminance (0-100)
+func yFromLstar(l float64) float64 {
+	_, y, _ := labToXYZ(l, 0, 0, [3]float64{1, 100, 1})
+	return y
+}
+
+// lerp interpolates linearly between a and b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCAM16 checks CAM16 against the reference values of Material's color utilities
+func TestCAM16(t *testing.T) {
+	tests := []struct {
+		hex     string
+		j, c, h float64
+	}{
+		{"#FF0000", 46.445, 113.357, 27.408},
+		{"#00FF00", 79.332, 108.410, 142.139},
+		{"#0000FF", 25.465, 87.230, 282.788},
+		{"#FFFFFF", 100.0, 2.869, 209.492},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			data, err := DetectFormat(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			j, c, h := linearToCAM16(data.Linear.R, data.Linear.G, data.Linear.B, cam16Default)
+			// Material uses a rounded D65 white, so allow a small tolerance
+			if math.Abs(j-tt.j) > 0.01 || math.Abs(c-tt.c) > 0.01 || math.Abs(h-tt.h) > 0.1 {
+				t.Errorf("expected J %.3f C %.3f h %.3f, got J %.3f C %.3f h %.3f", tt.j, tt.c, tt.h, j, c, h)
+			}
+
+			r, g, b := cam16ToLinear(j, c, h, cam16Default)
+			if math.Abs(r-data.Linear.R) > 1e-6 || math.Abs(g-data.Linear.G) > 1e-6 || math.Abs(b-data.Linear.B) > 1e-6 {
+				t.Errorf("round trip gave %v %v %v", r, g, b)
+			}
+		})
+	}
+}
+
+func TestHCTFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   string
+		expected string
+	}{
+		{"#6750A4", "hct", "hct(298.97 47.85 40.08)"},
+		{"#000000", "hct", "hct(0.00 0.00 0.00)"},
+		{"hct(282.76 87.23 32.30)", "hex", "#0000FF"},
+		{"hct(27.41 113.36 53.24 / 50%)", "hex", "#FF000080"},
+		{"HCT(298.97deg 47.85 40.08)", "hex", "#6750A4"},
+		// Chroma beyond sRGB is reduced at the same hue and tone
+		{"hct(120 200 50)", "hex", "#6D7F00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := Convert(tt.input, tt.target, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	format, err := DetectInputFormat("hct(120 40 50)")
+	if err != nil || format != string(FormatHCT) {
+		t.Errorf("expected format hct, got %s (%v)", format, err)
+	}
+}
+
+func TestHCTToLinearKeepsTone(t *testing.T) {
+	for _, hue := range []float64{0, 25, 90, 142, 209, 282, 300} {
+		for _, tone := range []float64{5, 30, 50, 80, 99} {
+			r, g, b := hctToLinear(hue, 80, tone)
+			_, y, _ := linearToXYZ(r, g, b)
+			if got := lstarFromY(y * 100); math.Abs(got-tone) > 0.05 {
+				t.Errorf("hue %v tone %v: got tone %.3f", hue, tone, got)
+			}
+		}
+	}
+}
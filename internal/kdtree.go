@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"sort"
+)
+
+// kdNode is a node of a 3-d tree, splitting on axis at its point
+type kdNode struct {
+	point       [3]float64
+	index       int // position of the point in the slice the tree was built from
+	axis        int
+	left, right *kdNode
+}
+
+// kdNeighbor is a point found by a nearest-neighbor search
+type kdNeighbor struct {
+	index    int
+	distance float64 // squared Euclidean distance to the query
+}
+
+// kdTree is a k-d tree over 3-d points, such as OKLab colors, for fast
+// nearest-neighbor queries on large palettes
+type kdTree struct {
+	root *kdNode
+	size int
+}
+
+// newKDTree builds a balanced tree by splitting at the median of each axis in turn
+func newKDTree(points [][3]float64) *kdTree {
+	nodes := make([]*kdNode, len(points))
+	for i, p := range points {
+		nodes[i] = &kdNode{point: p, index: i}
+	}
+	return &kdTree{root: buildKDTree(nodes, 0), size: len(points)}
+}
+
+func buildKDTree(nodes []*kdNode, depth int) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	axis := depth % 3
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].point[axis] != nodes[j].point[axis] {
+			return nodes[i].point[axis] < nodes[j].point[axis]
+		}
+		return nodes[i].index < nodes[j].index
+	})
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	node.axis = axis
+	node.left = buildKDTree(nodes[:mid], depth+1)
+	node.right = buildKDTree(nodes[mid+1:], depth+1)
+	return node
+}
+
+// nearest returns the k points closest to q, closest first; ties keep the
+// order the points were given in
+func (t *kdTree) nearest(q [3]float64, k int) []kdNeighbor {
+	if k > t.size {
+		k = t.size
+	}
+	if k <= 0 {
+		return nil
+	}
+	best := make([]kdNeighbor, 0, k+1)
+	t.search(t.root, q, k, &best)
+	return best
+}
+
+func (t *kdTree) search(node *kdNode, q [3]float64, k int, best *[]kdNeighbor) {
+	if node == nil {
+		return
+	}
+
+	d0 := node.point[0] - q[0]
+	d1 := node.point[1] - q[1]
+	d2 := node.point[2] - q[2]
+	insertNeighbor(best, kdNeighbor{index: node.index, distance: d0*d0 + d1*d1 + d2*d2}, k)
+
+	diff := q[node.axis] - node.point[node.axis]
+	near, far := node.left, node.right
+	if diff > 0 {
+		near, far = node.right, node.left
+	}
+	t.search(near, q, k, best)
+	// Only cross the splitting plane when it is closer than the current k-th neighbor
+	if len(*best) < k || diff*diff <= (*best)[len(*best)-1].distance {
+		t.search(far, q, k, best)
+	}
+}
+
+// insertNeighbor keeps best sorted by distance, then index, holding at most k entries
+func insertNeighbor(best *[]kdNeighbor, n kdNeighbor, k int) {
+	list := *best
+	i := sort.Search(len(list), func(i int) bool {
+		if list[i].distance != n.distance {
+			return list[i].distance > n.distance
+		}
+		return list[i].index > n.index
+	})
+	if i >= k {
+		return
+	}
+	list = append(list, kdNeighbor{})
+	copy(list[i+1:], list[i:])
+	list[i] = n
+	if len(list) > k {
+		list = list[:k]
+	}
+	*best = list
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDTreeNearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([][3]float64, 2000)
+	for i := range points {
+		points[i] = [3]float64{rng.Float64(), rng.Float64()*0.8 - 0.4, rng.Float64()*0.8 - 0.4}
+	}
+	tree := newKDTree(points)
+
+	for q := 0; q < 50; q++ {
+		query := [3]float64{rng.Float64(), rng.Float64()*0.8 - 0.4, rng.Float64()*0.8 - 0.4}
+
+		// Brute force reference
+		all := make([]kdNeighbor, len(points))
+		for i, p := range points {
+			d0, d1, d2 := p[0]-query[0], p[1]-query[1], p[2]-query[2]
+			all[i] = kdNeighbor{index: i, distance: d0*d0 + d1*d1 + d2*d2}
+		}
+		sort.Slice(all, func(i, j int) bool { return all[i].distance < all[j].distance })
+
+		for _, k := range []int{1, 5, 40} {
+			got := tree.nearest(query, k)
+			if len(got) != k {
+				t.Fatalf("k=%d: expected %d neighbors, got %d", k, k, len(got))
+			}
+			for i := range got {
+				if got[i].index != all[i].index {
+					t.Errorf("query %d, k=%d: neighbor %d is %d, expected %d", q, k, i, got[i].index, all[i].index)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestKDTreeSmall(t *testing.T) {
+	tree := newKDTree([][3]float64{{0, 0, 0}, {1, 0, 0}})
+	if got := tree.nearest([3]float64{0.9, 0, 0}, 5); len(got) != 2 || got[0].index != 1 {
+		t.Errorf("unexpected neighbors %+v", got)
+	}
+	if got := newKDTree(nil).nearest([3]float64{}, 3); len(got) != 0 {
+		t.Errorf("expected no neighbors in an empty tree, got %+v", got)
+	}
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaterialTones are the tones listed for each Material tonal palette
+var MaterialTones = []float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99, 100}
+
+// Material key palettes of a scheme, in the order of MaterialScheme.Palettes
+const (
+	materialPrimary = iota
+	materialSecondary
+	materialTertiary
+	materialNeutral
+	materialNeutralVariant
+	materialError
+)
+
+// materialPaletteNames names the key palettes
+var materialPaletteNames = []string{"primary", "secondary", "tertiary", "neutral", "neutralVariant", "error"}
+
+// materialRole maps a scheme role to a palette tone in the light and dark schemes
+type materialRole struct {
+	name    string
+	palette int
+	light   float64
+	dark    float64
+}
+
+// materialRoles are the Material 3 color roles of the baseline scheme
+var materialRoles = []materialRole{
+	{"primary", materialPrimary, 40, 80},
+	{"onPrimary", materialPrimary, 100, 20},
+	{"primaryContainer", materialPrimary, 90, 30},
+	{"onPrimaryContainer", materialPrimary, 10, 90},
+	{"secondary", materialSecondary, 40, 80},
+	{"onSecondary", materialSecondary, 100, 20},
+	{"secondaryContainer", materialSecondary, 90, 30},
+	{"onSecondaryContainer", materialSecondary, 10, 90},
+	{"tertiary", materialTertiary, 40, 80},
+	{"onTertiary", materialTertiary, 100, 20},
+	{"tertiaryContainer", materialTertiary, 90, 30},
+	{"onTertiaryContainer", materialTertiary, 10, 90},
+	{"error", materialError, 40, 80},
+	{"onError", materialError, 100, 20},
+	{"errorContainer", materialError, 90, 30},
+	{"onErrorContainer", materialError, 10, 90},
+	{"background", materialNeutral, 99, 10},
+	{"onBackground", materialNeutral, 10, 90},
+	{"surface", materialNeutral, 99, 10},
+	{"onSurface", materialNeutral, 10, 90},
+	{"surfaceVariant", materialNeutralVariant, 90, 30},
+	{"onSurfaceVariant", materialNeutralVariant, 30, 80},
+	{"outline", materialNeutralVariant, 50, 60},
+	{"outlineVariant", materialNeutralVariant, 80, 30},
+	{"shadow", materialNeutral, 0, 0},
+	{"scrim", materialNeutral, 0, 0},
+	{"inverseSurface", materialNeutral, 20, 90},
+	{"inverseOnSurface", materialNeutral, 95, 20},
+	{"inversePrimary", materialPrimary, 80, 40},
+}
+
+// TonalPalette is a Material tonal palette: one HCT hue and chroma at every tone
+type TonalPalette struct {
+	Name   string
+	Hue    float64
+	Chroma float64
+}
+
+// Tone returns the palette color at a tone (0-100) as hex
+func (p TonalPalette) Tone(tone float64) string {
+	r, g, b := hctToLinear(p.Hue, p.Chroma, tone)
+	c := LinearColor{R: r, G: g, B: b, A: AlphaMax}.SRGB()
+	return formatHEX(c.R, c.G, c.B, c.A)
+}
+
+// MaterialRole is one color role of a Material scheme
+type MaterialRole struct {
+	Name string
+	Tone float64
+	HEX  string
+}
+
+// MaterialScheme holds the tonal palettes and light and dark schemes built from a seed color
+type MaterialScheme struct {
+	Seed     ColorData
+	SeedHCT  [3]float64 // hue, chroma, tone
+	Palettes []TonalPalette
+	Light    []MaterialRole
+	Dark     []MaterialRole
+}
+
+// GenerateMaterialScheme builds the Material Design 3 key palettes and color
+// roles from a seed color, as Material's CorePalette and baseline Scheme do:
+// the primary palette keeps the seed hue with a chroma of at least 48, tertiary
+// is rotated by 60° and error is fixed at hue 25
+func GenerateMaterialScheme(seed string) (*MaterialScheme, error) {
+	data, err := DetectFormat(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+
+	h, c, t := linearToHCT(data.Linear.R, data.Linear.G, data.Linear.B)
+	result := &MaterialScheme{
+		Seed:    data,
+		SeedHCT: [3]float64{h, c, t},
+		Palettes: []TonalPalette{
+			materialPrimary:        {Hue: h, Chroma: math.Max(48, c)},
+			materialSecondary:      {Hue: h, Chroma: 16},
+			materialTertiary:       {Hue: normalizeHue(h + 60), Chroma: 24},
+			materialNeutral:        {Hue: h, Chroma: 4},
+			materialNeutralVariant: {Hue: h, Chroma: 8},
+			materialError:          {Hue: 25, Chroma: 84},
+		},
+	}
+	for i := range result.Palettes {
+		result.Palettes[i].Name = materialPaletteNames[i]
+	}
+
+	for _, role := range materialRoles {
+		palette := result.Palettes[role.palette]
+		result.Light = append(result.Light, MaterialRole{Name: role.name, Tone: role.light, HEX: palette.Tone(role.light)})
+		result.Dark = append(result.Dark, MaterialRole{Name: role.name, Tone: role.dark, HEX: palette.Tone(role.dark)})
+	}
+
+	return result, nil
+}
+
+// FormatMaterialScheme formats a Material scheme as text
+func FormatMaterialScheme(result *MaterialScheme) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Material scheme from %s (%s)\n", result.Seed.Original, result.Seed.Format))
+	builder.WriteString(fmt.Sprintf("Seed HCT: hue %.2f, chroma %.2f, tone %.2f\n\n", result.SeedHCT[0], result.SeedHCT[1], result.SeedHCT[2]))
+
+	builder.WriteString("Tonal palettes:\n")
+	for _, p := range result.Palettes {
+		builder.WriteString(fmt.Sprintf("  %s (hue %.2f, chroma %.2f)\n   ", p.Name, p.Hue, p.Chroma))
+		for _, tone := range MaterialTones {
+			builder.WriteString(fmt.Sprintf(" %s:%s", strconv.FormatFloat(tone, 'f', -1, 64), p.Tone(tone)))
+		}
+		builder.WriteString("\n")
+	}
+
+	for _, scheme := range []struct {
+		name  string
+		roles []MaterialRole
+	}{{"Light", result.Light}, {"Dark", result.Dark}} {
+		builder.WriteString(fmt.Sprintf("\n%s scheme:\n", scheme.name))
+		for _, role := range scheme.roles {
+			builder.WriteString(fmt.Sprintf("  %-21s %s  (tone %s)\n", role.Name, role.HEX, strconv.FormatFloat(role.Tone, 'f', -1, 64)))
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
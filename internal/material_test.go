@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestGenerateMaterialScheme(t *testing.T) {
+	result, err := GenerateMaterialScheme("#6750A4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Palettes) != 6 {
+		t.Fatalf("expected 6 palettes, got %d", len(result.Palettes))
+	}
+
+	light := map[string]string{}
+	for _, role := range result.Light {
+		light[role.Name] = role.HEX
+	}
+	dark := map[string]string{}
+	for _, role := range result.Dark {
+		dark[role.Name] = role.HEX
+	}
+
+	expectedLight := map[string]string{
+		"primary":        "#6750A4",
+		"onPrimary":      "#FFFFFF",
+		"secondary":      "#625B71",
+		"error":          "#BA1A1A",
+		"background":     "#FFFBFF",
+		"onSurface":      "#1C1B1E",
+		"outline":        "#7A757F",
+		"scrim":          "#000000",
+		"inverseSurface": "#313033",
+	}
+	for name, hex := range expectedLight {
+		if light[name] != hex {
+			t.Errorf("light %s: expected %s, got %s", name, hex, light[name])
+		}
+	}
+	if dark["primary"] != light["inversePrimary"] || dark["inversePrimary"] != light["primary"] {
+		t.Errorf("expected dark primary and inverse primary to swap with light")
+	}
+	if dark["onPrimary"] != "#381E72" {
+		t.Errorf("dark onPrimary: expected #381E72, got %s", dark["onPrimary"])
+	}
+}
+
+func TestGenerateMaterialSchemeGraySeed(t *testing.T) {
+	// A gray seed still gets a primary chroma of 48 at the seed's CAM16 hue
+	result, err := GenerateMaterialScheme("#808080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex := result.Palettes[materialPrimary].Tone(40); hex != "#006874" {
+		t.Errorf("expected #006874, got %s", hex)
+	}
+	if _, err := GenerateMaterialScheme("nope"); err == nil {
+		t.Error("expected error for invalid color")
+	}
+}
@@ -0,0 +1,505 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FormatColorMix is the detected format of a CSS color-mix() expression
+const FormatColorMix ColorFormat = "color-mix"
+
+// HueInterpolation selects how hues are interpolated around the color wheel
+type HueInterpolation string
+
+const (
+	HueShorter    HueInterpolation = "shorter"    // take the shorter arc (default)
+	HueLonger     HueInterpolation = "longer"     // take the longer arc
+	HueIncreasing HueInterpolation = "increasing" // always move counter-clockwise (hue grows)
+	HueDecreasing HueInterpolation = "decreasing" // always move clockwise (hue shrinks)
+)
+
+// GetHueInterpolations returns the supported hue interpolation methods
+func GetHueInterpolations() []string {
+	return []string{
+		string(HueShorter),
+		string(HueLonger),
+		string(HueIncreasing),
+		string(HueDecreasing),
+	}
+}
+
+// ParseHueInterpolation validates a hue interpolation method; empty selects shorter
+func ParseHueInterpolation(method string) (HueInterpolation, error) {
+	switch m := HueInterpolation(strings.ToLower(strings.TrimSpace(method))); m {
+	case "":
+		return HueShorter, nil
+	case HueShorter, HueLonger, HueIncreasing, HueDecreasing:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid hue interpolation: %s (supported: %s)", method, strings.Join(GetHueInterpolations(), ", "))
+	}
+}
+
+// interpolationSpace describes the coordinates colors are mixed in
+type interpolationSpace struct {
+	hue        int // index of the hue coordinate, -1 if there is none
+	toCoords   func(lc LinearColor) [3]float64
+	fromCoords func(c [3]float64) LinearColor
+	// achromatic reports whether the hue of the coordinates is powerless
+	achromatic func(c [3]float64) bool
+}
+
+// predefinedInterpolation mixes in the channels of a color() space
+func predefinedInterpolation(format ColorFormat) interpolationSpace {
+	space := predefinedSpaces[format]
+	return interpolationSpace{
+		hue: -1,
+		toCoords: func(lc LinearColor) [3]float64 {
+			c1, c2, c3 := linearToPredefined(space, lc.R, lc.G, lc.B)
+			return [3]float64{c1, c2, c3}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := predefinedToLinear(space, c[0], c[1], c[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	}
+}
+
+// Chroma below which a polar color is treated as achromatic and its hue as missing
+const (
+	achromaticOKLCH = 1e-4
+	achromaticLCH   = 1e-2
+	achromaticHSL   = 1e-2
+)
+
+// interpolationSpaces holds every <color-space> accepted by CSS color-mix()
+var interpolationSpaces = map[ColorFormat]interpolationSpace{
+	FormatSRGB:        predefinedInterpolation(FormatSRGB),
+	FormatSRGBLinear:  predefinedInterpolation(FormatSRGBLinear),
+	FormatDisplayP3:   predefinedInterpolation(FormatDisplayP3),
+	FormatA98RGB:      predefinedInterpolation(FormatA98RGB),
+	FormatProPhotoRGB: predefinedInterpolation(FormatProPhotoRGB),
+	FormatRec2020:     predefinedInterpolation(FormatRec2020),
+	FormatXYZ:         predefinedInterpolation(FormatXYZD65),
+	FormatXYZD50:      predefinedInterpolation(FormatXYZD50),
+	FormatXYZD65:      predefinedInterpolation(FormatXYZD65),
+	FormatLAB: {
+		hue: -1,
+		toCoords: func(lc LinearColor) [3]float64 {
+			l, a, b := linearToLAB(lc.R, lc.G, lc.B)
+			return [3]float64{l, a, b}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := labToLinear(c[0], c[1], c[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	},
+	FormatOKLab: {
+		hue: -1,
+		toCoords: func(lc LinearColor) [3]float64 {
+			l, a, b := linearToOKLab(lc.R, lc.G, lc.B)
+			return [3]float64{l, a, b}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := oklabToLinear(c[0], c[1], c[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	},
+	FormatLCH: {
+		hue: 2,
+		toCoords: func(lc LinearColor) [3]float64 {
+			l, c, h := linearToLCH(lc.R, lc.G, lc.B)
+			return [3]float64{l, c, h}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := lchToLinear(c[0], c[1], c[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+		achromatic: func(c [3]float64) bool { return c[1] < achromaticLCH },
+	},
+	FormatOKLCH: {
+		hue: 2,
+		toCoords: func(lc LinearColor) [3]float64 {
+			l, c, h := linearToOKLCH(lc.R, lc.G, lc.B)
+			return [3]float64{l, c, h}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := oklchToLinear(c[0], c[1], c[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+		achromatic: func(c [3]float64) bool { return c[1] < achromaticOKLCH },
+	},
+	FormatHSL: {
+		hue: 0,
+		toCoords: func(lc LinearColor) [3]float64 {
+			c := lc.SRGB()
+			h, s, l := rgbToHSL(c.R, c.G, c.B)
+			return [3]float64{h, s, l}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := hslToRGB(c[0], c[1], c[2])
+			return Color{R: r, G: g, B: b}.Linear()
+		},
+		achromatic: func(c [3]float64) bool { return c[1] < achromaticHSL },
+	},
+	FormatHWB: {
+		hue: 0,
+		toCoords: func(lc LinearColor) [3]float64 {
+			c := lc.SRGB()
+			h, w, b := rgbToHWB(c.R, c.G, c.B)
+			return [3]float64{h, w, b}
+		},
+		fromCoords: func(c [3]float64) LinearColor {
+			r, g, b := hwbToRGB(c[0], c[1], c[2])
+			return Color{R: r, G: g, B: b}.Linear()
+		},
+		achromatic: func(c [3]float64) bool { return c[1]+c[2] >= LightnessMax-achromaticHSL },
+	},
+}
+
+// GetInterpolationSpaces returns the color spaces colors can be mixed in
+func GetInterpolationSpaces() []string {
+	return []string{
+		"srgb", "srgb-linear", "display-p3", "a98-rgb", "prophoto-rgb", "rec2020",
+		"lab", "oklab", "xyz", "xyz-d50", "xyz-d65",
+		"hsl", "hwb", "lch", "oklch",
+	}
+}
+
+// ParseInterpolationSpace validates an interpolation space name; empty selects oklab
+func ParseInterpolationSpace(name string) (ColorFormat, error) {
+	space := ColorFormat(strings.ToLower(strings.TrimSpace(name)))
+	if space == "" {
+		return FormatOKLab, nil
+	}
+	if _, ok := interpolationSpaces[space]; !ok {
+		return "", fmt.Errorf("invalid interpolation space: %s (supported: %s)", name, strings.Join(GetInterpolationSpaces(), ", "))
+	}
+	return space, nil
+}
+
+// mixColors interpolates between two colors in the given space with premultiplied alpha
+// t is the weight of c2: 0 returns c1, 1 returns c2
+func mixColors(c1, c2 LinearColor, t float64, space ColorFormat, method HueInterpolation) LinearColor {
+	is := interpolationSpaces[space]
+	p1 := is.toCoords(c1)
+	p2 := is.toCoords(c2)
+
+	// A powerless hue takes the hue of the other color
+	if is.hue >= 0 {
+		achromatic1 := is.achromatic(p1)
+		achromatic2 := is.achromatic(p2)
+		if achromatic1 && !achromatic2 {
+			p1[is.hue] = p2[is.hue]
+		} else if achromatic2 && !achromatic1 {
+			p2[is.hue] = p1[is.hue]
+		}
+	}
+
+	alpha := c1.A*(1-t) + c2.A*t
+
+	var mixed [3]float64
+	for i := range mixed {
+		if i == is.hue {
+			mixed[i] = interpolateHue(p1[i], p2[i], t, method)
+			continue
+		}
+		// Premultiply so a transparent color does not pull the others toward its channels
+		if alpha > 0 {
+			mixed[i] = (p1[i]*c1.A*(1-t) + p2[i]*c2.A*t) / alpha
+		} else {
+			mixed[i] = p1[i]*(1-t) + p2[i]*t
+		}
+	}
+
+	lc := is.fromCoords(mixed)
+	lc.A = alpha
+	return lc
+}
+
+// InterpolatedColor is a color sampled between interpolation stops
+type InterpolatedColor struct {
+	Position float64 // 0-1 along the stops
+	Color    string  // serialized in the requested format
+}
+
+// InterpolationResult lists colors sampled along a sequence of stops
+type InterpolationResult struct {
+	Stops  []ColorData
+	Space  ColorFormat
+	Hue    HueInterpolation
+	Format string
+	Colors []InterpolatedColor
+}
+
+// InterpolateColors samples colors at the given positions (0-1) along two or more
+// evenly spaced stops, interpolating in the given space and serializing each result
+// in the target format
+func InterpolateColors(stops []string, positions []float64, space ColorFormat, method HueInterpolation, targetFormat string, opts ConvertOptions) (*InterpolationResult, error) {
+	if len(stops) < 2 {
+		return nil, fmt.Errorf("at least two colors are required to interpolate")
+	}
+
+	space, err := ParseInterpolationSpace(string(space))
+	if err != nil {
+		return nil, err
+	}
+	method, err = ParseHueInterpolation(string(method))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InterpolationResult{Space: space, Hue: method, Format: targetFormat}
+	linear := make([]LinearColor, len(stops))
+	for i, stop := range stops {
+		data, err := DetectFormat(stop)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q: %w", stop, err)
+		}
+		result.Stops = append(result.Stops, data)
+		linear[i] = data.Linear
+	}
+
+	for _, pos := range positions {
+		if pos < 0 || pos > 1 {
+			return nil, fmt.Errorf("position %g is outside 0-1", pos)
+		}
+		lc := interpolateStops(linear, pos, space, method)
+		color, err := FormatColor(ColorData{Color: lc.SRGB(), Linear: lc}, targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Colors = append(result.Colors, InterpolatedColor{Position: pos, Color: color})
+	}
+
+	return result, nil
+}
+
+// interpolateStops returns the color at position t (0-1) along evenly spaced stops
+func interpolateStops(stops []LinearColor, t float64, space ColorFormat, method HueInterpolation) LinearColor {
+	segments := float64(len(stops) - 1)
+	i := int(t * segments)
+	if i >= len(stops)-1 {
+		i = len(stops) - 2
+	}
+	return mixColors(stops[i], stops[i+1], t*segments-float64(i), space, method)
+}
+
+// FormatInterpolation formats interpolated colors as text
+func FormatInterpolation(result *InterpolationResult) string {
+	var builder strings.Builder
+	stops := make([]string, len(result.Stops))
+	for i, stop := range result.Stops {
+		stops[i] = stop.Original
+	}
+	builder.WriteString(fmt.Sprintf("Interpolation: %s\n", strings.Join(stops, " → ")))
+	builder.WriteString(fmt.Sprintf("Space: %s", result.Space))
+	if interpolationSpaces[result.Space].hue >= 0 {
+		builder.WriteString(fmt.Sprintf(" (%s hue)", result.Hue))
+	}
+	builder.WriteString("\n\n")
+
+	for _, c := range result.Colors {
+		builder.WriteString(fmt.Sprintf("  %.3f: %s\n", c.Position, c.Color))
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// interpolateHue interpolates two hues in degrees following the CSS hue interpolation method
+func interpolateHue(h1, h2, t float64, method HueInterpolation) float64 {
+	diff := h2 - h1
+	switch method {
+	case HueLonger:
+		if diff > 0 && diff < FullCircle/2 {
+			h1 += FullCircle
+		} else if diff > -FullCircle/2 && diff <= 0 {
+			h2 += FullCircle
+		}
+	case HueIncreasing:
+		if h2 < h1 {
+			h2 += FullCircle
+		}
+	case HueDecreasing:
+		if h1 < h2 {
+			h1 += FullCircle
+		}
+	default: // shorter
+		if diff > FullCircle/2 {
+			h1 += FullCircle
+		} else if diff < -FullCircle/2 {
+			h2 += FullCircle
+		}
+	}
+	return normalizeHue(h1 + (h2-h1)*t)
+}
+
+// colorMixPattern matches the start of a CSS color-mix() expression
+var colorMixPattern = regexp.MustCompile(`(?i)^color-mix\s*\(`)
+
+// parseColorMix evaluates a CSS color-mix() expression such as
+// color-mix(in oklch longer hue, #f00 30%, blue)
+func parseColorMix(input string) (ColorData, error) {
+	open := strings.Index(input, "(")
+	if !strings.HasSuffix(input, ")") {
+		return ColorData{}, fmt.Errorf("invalid color-mix(): %s", input)
+	}
+	parts, err := splitTopLevel(input[open+1:len(input)-1], ',')
+	if err != nil {
+		return ColorData{}, fmt.Errorf("invalid color-mix() %s: %w", input, err)
+	}
+
+	// The interpolation method is optional and defaults to oklab
+	space, method := FormatOKLab, HueShorter
+	if len(parts) == 3 {
+		space, method, err = parseInterpolationMethod(parts[0])
+		if err != nil {
+			return ColorData{}, fmt.Errorf("invalid color-mix() %s: %w", input, err)
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 2 {
+		return ColorData{}, fmt.Errorf("invalid color-mix(): %s (expected two colors)", input)
+	}
+
+	var colors [2]LinearColor
+	var pcts [2]float64
+	var hasPct [2]bool
+	for i, part := range parts {
+		colors[i], pcts[i], hasPct[i], err = parseMixComponent(part)
+		if err != nil {
+			return ColorData{}, fmt.Errorf("invalid color-mix() %s: %w", input, err)
+		}
+	}
+
+	// Percentage normalization (CSS Color 5 §2.1)
+	switch {
+	case !hasPct[0] && !hasPct[1]:
+		pcts = [2]float64{50, 50}
+	case !hasPct[1]:
+		pcts[1] = 100 - pcts[0]
+	case !hasPct[0]:
+		pcts[0] = 100 - pcts[1]
+	}
+	sum := pcts[0] + pcts[1]
+	if sum <= 0 {
+		return ColorData{}, fmt.Errorf("invalid color-mix(): %s (percentages add up to zero)", input)
+	}
+	// When the percentages add up to less than 100%, the remainder becomes transparency
+	alphaMultiplier := math.Min(sum/100, 1)
+
+	mixed := mixColors(colors[0], colors[1], pcts[1]/sum, space, method)
+	mixed.A = clamp(mixed.A*alphaMultiplier, AlphaMin, AlphaMax)
+
+	return ColorData{
+		Color:    mixed.SRGB(),
+		Linear:   mixed,
+		Format:   FormatColorMix,
+		Original: input,
+	}, nil
+}
+
+// parseInterpolationMethod parses "in <space> [<hue-method> hue]"
+func parseInterpolationMethod(s string) (ColorFormat, HueInterpolation, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) < 2 || fields[0] != "in" {
+		return "", "", fmt.Errorf("expected 'in <color-space>', got %q", s)
+	}
+	space, err := ParseInterpolationSpace(fields[1])
+	if err != nil {
+		return "", "", err
+	}
+
+	method := HueShorter
+	switch len(fields) {
+	case 2:
+	case 4:
+		if fields[3] != "hue" {
+			return "", "", fmt.Errorf("expected '<method> hue', got %q", strings.Join(fields[2:], " "))
+		}
+		if interpolationSpaces[space].hue < 0 {
+			return "", "", fmt.Errorf("hue interpolation requires a polar color space, got %s", space)
+		}
+		method, err = ParseHueInterpolation(fields[2])
+		if err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", fmt.Errorf("invalid interpolation method %q", s)
+	}
+	return space, method, nil
+}
+
+// parseMixComponent parses "<color> [<percentage>]", with the percentage on either side
+func parseMixComponent(s string) (LinearColor, float64, bool, error) {
+	tokens, err := splitColorArgs(s)
+	if err != nil {
+		return LinearColor{}, 0, false, err
+	}
+
+	colorStr := ""
+	pct, hasPct := 0.0, false
+	for _, tok := range tokens {
+		if v, ok := parsePercentage(tok); ok && !hasPct {
+			if v < 0 || v > 100 {
+				return LinearColor{}, 0, false, fmt.Errorf("percentage %s is outside 0%%-100%%", tok)
+			}
+			pct, hasPct = v, true
+			continue
+		}
+		if colorStr != "" {
+			return LinearColor{}, 0, false, fmt.Errorf("unexpected %q after color %s", tok, colorStr)
+		}
+		colorStr = tok
+	}
+	if colorStr == "" {
+		return LinearColor{}, 0, false, fmt.Errorf("missing color in %q", s)
+	}
+
+	data, err := DetectFormat(colorStr)
+	if err != nil {
+		return LinearColor{}, 0, false, err
+	}
+	return data.Linear, pct, hasPct, nil
+}
+
+// parsePercentage parses a token such as "30%" or "12.5%"
+func parsePercentage(tok string) (float64, bool) {
+	if !strings.HasSuffix(tok, "%") {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(tok, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside parentheses
+func splitTopLevel(s string, sep byte) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case sep:
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return append(parts, strings.TrimSpace(s[start:])), nil
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColorMix(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string // hex of the resolved color
+	}{
+		{"color-mix(in srgb, red, blue)", "#800080"},
+		{"color-mix(in srgb, red 20%, blue 20%)", "#80008066"},
+		{"color-mix(in srgb-linear, red 60%, blue 60%)", "#BC00BC"},
+		{"color-mix(in srgb, rgb(255 0 0 / 0.5), blue)", "#5500AABF"},
+		{"color-mix(in oklch, #f00 30%, blue)", "#8800EC"},
+		{"color-mix(in oklab, 25% red, blue)", "#5147D2"},
+		{"color-mix(red, blue)", "#8C53A2"},
+		{"color-mix(in hsl longer hue, red, blue)", "#00FF00"},
+		{"color-mix(in lch increasing hue, red, white)", "#FF9F80"},
+		{"color-mix(in xyz, red, color-mix(in srgb, blue, white))", "#CC5CBC"},
+		{"color-mix(in srgb, red 100%, blue)", "#FF0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := DetectInputFormat(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != string(FormatColorMix) {
+				t.Errorf("expected format color-mix, got %s", format)
+			}
+			result, err := Convert(tt.input, "hex", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestColorMixErrors(t *testing.T) {
+	inputs := []string{
+		"color-mix(in srgb longer hue, red, blue)",
+		"color-mix(in srgb, red 0%, blue 0%)",
+		"color-mix(in cmyk, red, blue)",
+		"color-mix(in srgb, red)",
+		"color-mix(in srgb, red 120%, blue)",
+		"color-mix(in oklch sideways hue, red, blue)",
+		"color-mix(in srgb, red blue, white)",
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := DetectFormat(input); err == nil {
+				t.Errorf("expected error for %s", input)
+			}
+		})
+	}
+}
+
+func TestInterpolateHue(t *testing.T) {
+	tests := []struct {
+		h1, h2   float64
+		method   HueInterpolation
+		expected float64
+	}{
+		{10, 350, HueShorter, 0},
+		{10, 350, HueLonger, 180},
+		{10, 350, HueIncreasing, 180},
+		{10, 350, HueDecreasing, 0},
+		{350, 10, HueIncreasing, 0},
+		{350, 10, HueDecreasing, 180},
+		{30, 90, HueShorter, 60},
+		{30, 90, HueLonger, 240},
+	}
+	for _, tt := range tests {
+		h := interpolateHue(tt.h1, tt.h2, 0.5, tt.method)
+		if math.Abs(h-tt.expected) > 1e-9 {
+			t.Errorf("%s %v->%v: expected %v, got %v", tt.method, tt.h1, tt.h2, tt.expected, h)
+		}
+	}
+}
+
+// TestMixColorsPowerlessHue checks that an achromatic color does not drag the hue toward 0
+func TestMixColorsPowerlessHue(t *testing.T) {
+	blue := Color{R: 0, G: 0, B: 255, A: 1}.Linear()
+	white := Color{R: 255, G: 255, B: 255, A: 1}.Linear()
+	_, _, hBlue := linearToOKLCH(blue.R, blue.G, blue.B)
+
+	mixed := mixColors(blue, white, 0.5, FormatOKLCH, HueShorter)
+	_, _, h := linearToOKLCH(mixed.R, mixed.G, mixed.B)
+	if calculateHueDifference(h, hBlue) > 0.5 {
+		t.Errorf("expected hue %.2f, got %.2f", hBlue, h)
+	}
+}
+
+func TestInterpolateColors(t *testing.T) {
+	tests := []struct {
+		name      string
+		stops     []string
+		positions []float64
+		space     ColorFormat
+		method    HueInterpolation
+		format    string
+		expected  []string
+	}{
+		{"srgb endpoints", []string{"red", "blue"}, []float64{0, 0.5, 1}, FormatSRGB, "", "hex", []string{"#FF0000", "#800080", "#0000FF"}},
+		{"default oklab", []string{"red", "blue"}, []float64{0.5}, "", "", "hex", []string{"#8C53A2"}},
+		{"three stops", []string{"black", "red", "white"}, []float64{0.5}, FormatSRGB, "", "rgb", []string{"rgb(255, 0, 0)"}},
+		{"oklch longer", []string{"red", "blue", "lime"}, []float64{0.25, 1}, FormatOKLCH, HueLonger, "oklch", []string{"oklch(0.5400 0.2854 146.64)", "oklch(0.8664 0.2948 142.50)"}},
+		{"hsl", []string{"red", "lime"}, []float64{0.5}, FormatHSL, "", "hsl", []string{"hsl(60, 100%, 50%)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := InterpolateColors(tt.stops, tt.positions, tt.space, tt.method, tt.format, ConvertOptions{PreserveAlpha: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Colors) != len(tt.expected) {
+				t.Fatalf("expected %d colors, got %d", len(tt.expected), len(result.Colors))
+			}
+			for i, c := range result.Colors {
+				if c.Color != tt.expected[i] {
+					t.Errorf("position %v: expected %s, got %s", c.Position, tt.expected[i], c.Color)
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolateColorsErrors(t *testing.T) {
+	if _, err := InterpolateColors([]string{"red"}, []float64{0.5}, "", "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for a single color")
+	}
+	if _, err := InterpolateColors([]string{"red", "blue"}, []float64{1.5}, "", "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for a position outside 0-1")
+	}
+	if _, err := InterpolateColors([]string{"red", "blue"}, []float64{0.5}, "cmyk", "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for an unknown space")
+	}
+	if _, err := InterpolateColors([]string{"red", "blue"}, []float64{0.5}, "", "", "pantone", ConvertOptions{}); err == nil {
+		t.Error("expected error for an unknown target format")
+	}
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestNamedColorCount(t *testing.T) {
+	if len(namedColors) != 148 {
+		t.Errorf("expected 148 CSS named colors, got %d", len(namedColors))
+	}
+}
+
+func TestNamedColorParsing(t *testing.T) {
+	tests := []struct {
+		input   string
+		expectR float64
+		expectG float64
+		expectB float64
+		expectA float64
+	}{
+		{"rebeccapurple", 102, 51, 153, 1},
+		{"CornflowerBlue", 100, 149, 237, 1},
+		{"RED", 255, 0, 0, 1},
+		{" navy ", 0, 0, 128, 1},
+		{"transparent", 0, 0, 0, 0},
+		{"Transparent", 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			data, err := DetectFormat(tt.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.input, err)
+			}
+			if data.Format != FormatNamed {
+				t.Errorf("Expected format %s, got %s", FormatNamed, data.Format)
+			}
+			if data.Color.R != tt.expectR || data.Color.G != tt.expectG || data.Color.B != tt.expectB {
+				t.Errorf("Expected RGB(%f, %f, %f), got RGB(%f, %f, %f)",
+					tt.expectR, tt.expectG, tt.expectB,
+					data.Color.R, data.Color.G, data.Color.B)
+			}
+			if data.Color.A != tt.expectA {
+				t.Errorf("Expected alpha %f, got %f", tt.expectA, data.Color.A)
+			}
+		})
+	}
+}
+
+func TestConvertToNamed(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		fallback  bool
+		expected  string
+		expectErr bool
+	}{
+		{"exact match", "#663399", false, "rebeccapurple", false},
+		{"alias resolves alphabetically", "#00FFFF", false, "aqua", false},
+		{"gray alias", "rgb(128, 128, 128)", false, "gray", false},
+		{"transparent", "rgba(0, 0, 0, 0)", false, "transparent", false},
+		{"no exact match", "#FF0001", false, "", true},
+		{"nearest fallback", "#FF0001", true, "red", false},
+		{"nearest fallback blue-ish", "#6495EE", true, "cornflowerblue", false},
+		{"translucent", "rgba(255, 0, 0, 0.5)", false, "", true},
+		{"translucent with fallback", "#FF000180", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertWithOptions(tt.input, "named", ConvertOptions{
+				PreserveAlpha: true,
+				NamedFallback: tt.fallback,
+			})
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error, got %s", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+
+	// Without alpha preservation the keyword is returned for translucent input
+	result, err := ConvertWithOptions("rgba(255, 0, 0, 0.5)", "named", ConvertOptions{})
+	if err != nil || result != "red" {
+		t.Errorf("Expected red without alpha, got %s (%v)", result, err)
+	}
+}
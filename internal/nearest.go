@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Nearest color search limits
+const (
+	DefaultNearestCount = 5
+	MaxNearestCount     = 50
+	// nearestIndexSize is the custom palette size from which a k-d tree is
+	// built; smaller palettes are scanned
+	nearestIndexSize = 256
+)
+
+// NearestMatch is a palette entry close to the query color
+type NearestMatch struct {
+	Name    string // empty for unnamed entries
+	Color   string // the entry in the requested format
+	HEX     string
+	DeltaE  float64 // difference from the query under the chosen metric
+	Verdict VerdictType
+}
+
+// NearestResult holds the closest palette entries to a color
+type NearestResult struct {
+	Query   ColorData
+	Palette string // built-in palette name, or "custom"
+	Size    int
+	Metric  DeltaEMetric
+	Matches []NearestMatch
+}
+
+// paletteIndex holds the parsed colors of a palette and, for the built-in
+// palettes, a k-d tree over their OKLab coordinates
+type paletteIndex struct {
+	entries []PaletteEntry
+	colors  []Color       // clamped sRGB, for output
+	linear  []LinearColor // unclamped, for the color difference
+	tree    *kdTree       // nil for small custom palettes, which are scanned
+}
+
+// builtinIndex lazily builds the index of a built-in palette once
+type builtinIndex struct {
+	once  sync.Once
+	index *paletteIndex
+	err   error
+}
+
+// builtinIndexes caches the index of every built-in palette
+var builtinIndexes = map[BuiltinPalette]*builtinIndex{
+	PaletteCSS:      {},
+	PaletteTailwind: {},
+	PaletteMaterial: {},
+	PaletteWebSafe:  {},
+}
+
+// newPaletteIndex parses the colors of a palette
+func newPaletteIndex(palette []PaletteEntry) (*paletteIndex, error) {
+	if len(palette) == 0 {
+		return nil, fmt.Errorf("palette cannot be empty")
+	}
+	index := &paletteIndex{entries: palette, colors: make([]Color, len(palette)), linear: make([]LinearColor, len(palette))}
+	for i, entry := range palette {
+		data, err := DetectFormat(entry.Color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid palette color %q: %w", entry.Color, err)
+		}
+		index.colors[i] = data.Color
+		index.linear[i] = data.Linear
+	}
+	return index, nil
+}
+
+// buildTree indexes the palette colors in a k-d tree over OKLab
+func (p *paletteIndex) buildTree() {
+	points := make([][3]float64, len(p.linear))
+	for i, lc := range p.linear {
+		points[i] = linearToOKLabPoint(lc)
+	}
+	p.tree = newKDTree(points)
+}
+
+// getBuiltinIndex returns the cached index of a built-in palette, building it
+// and its k-d tree on first use
+func getBuiltinIndex(name BuiltinPalette) (*paletteIndex, BuiltinPalette, error) {
+	name = BuiltinPalette(strings.ToLower(strings.TrimSpace(string(name))))
+	cached, ok := builtinIndexes[name]
+	if !ok {
+		return nil, "", fmt.Errorf("invalid palette: %s (supported: %s)", name, strings.Join(GetBuiltinPalettes(), ", "))
+	}
+	cached.once.Do(func() {
+		palette, err := GetBuiltinPalette(name)
+		if err != nil {
+			cached.err = err
+			return
+		}
+		index, err := newPaletteIndex(palette)
+		if err != nil {
+			cached.err = err
+			return
+		}
+		index.buildTree()
+		cached.index = index
+	})
+	return cached.index, name, cached.err
+}
+
+// FindNearestColor returns the k entries of a custom palette closest to a
+// color under a metric, closest first. Palettes of 256 colors or more are
+// indexed in an OKLab k-d tree for deltaEOK queries; other metrics compare
+// every entry, so the result is exact for all metrics.
+func FindNearestColor(color string, palette []PaletteEntry, k int, metric DeltaEMetric, targetFormat string, opts ConvertOptions) (*NearestResult, error) {
+	query, err := DetectFormat(color)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+	index, err := newPaletteIndex(palette)
+	if err != nil {
+		return nil, err
+	}
+	if len(palette) >= nearestIndexSize {
+		index.buildTree()
+	}
+	return findNearest(query, index, "custom", k, metric, targetFormat, opts)
+}
+
+// FindNearestBuiltinColor returns the k entries of a built-in palette closest
+// to a color under a metric, closest first. With deltaEOK the cached OKLab
+// k-d tree answers the query; other metrics compare every entry, so the
+// result is exact for all metrics.
+func FindNearestBuiltinColor(color string, name BuiltinPalette, k int, metric DeltaEMetric, targetFormat string, opts ConvertOptions) (*NearestResult, error) {
+	query, err := DetectFormat(color)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+	index, name, err := getBuiltinIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	return findNearest(query, index, string(name), k, metric, targetFormat, opts)
+}
+
+// findNearest searches an index with the k-d tree when it has one and the
+// metric is deltaEOK, and by a full scan otherwise
+func findNearest(query ColorData, index *paletteIndex, paletteName string, k int, metric DeltaEMetric, targetFormat string, opts ConvertOptions) (*NearestResult, error) {
+	if k == 0 {
+		k = DefaultNearestCount
+	}
+	if k < 1 || k > MaxNearestCount {
+		return nil, fmt.Errorf("count must be between 1 and %d, got %d", MaxNearestCount, k)
+	}
+	metric, err := ParseDeltaEMetric(string(metric))
+	if err != nil {
+		return nil, err
+	}
+
+	var order []int
+	if index.tree != nil && metric == MetricDeltaEOK {
+		for _, n := range index.tree.nearest(linearToOKLabPoint(query.Linear), k) {
+			order = append(order, n.index)
+		}
+	} else {
+		order = make([]int, len(index.colors))
+		distances := make([]float64, len(index.colors))
+		for i, lc := range index.linear {
+			order[i] = i
+			distances[i] = calculateDeltaE(metric, query.Linear, lc)
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return distances[order[i]] < distances[order[j]]
+		})
+		if len(order) > k {
+			order = order[:k]
+		}
+	}
+
+	matches := make([]NearestMatch, len(order))
+	for i, n := range order {
+		c := index.colors[n]
+		deltaE := calculateDeltaE(metric, query.Linear, index.linear[n])
+		matches[i] = NearestMatch{Name: index.entries[n].Name, DeltaE: deltaE, Verdict: determineMetricVerdict(metric, deltaE)}
+		matches[i].HEX = formatHEX(c.R, c.G, c.B, c.A)
+		matches[i].Color, err = FormatColor(ColorData{Color: c, Linear: c.Linear()}, targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &NearestResult{Query: query, Palette: paletteName, Size: len(index.entries), Metric: metric, Matches: matches}, nil
+}
+
+// colorToOKLab returns the OKLab coordinates of an sRGB color, the space
+// calculateOKLCHDeltaE measures in
+func colorToOKLab(c Color) [3]float64 {
+	return linearToOKLabPoint(c.Linear())
+}
+
+// linearToOKLabPoint returns the OKLab coordinates of an unclamped linear
+// color, the space deltaEOK measures in
+func linearToOKLabPoint(lc LinearColor) [3]float64 {
+	l, a, b := linearToOKLab(lc.R, lc.G, lc.B)
+	return [3]float64{l, a, b}
+}
+
+// FormatNearestColor formats a nearest color search as text
+func FormatNearestColor(result *NearestResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Nearest to %s in %s (%d colors), by %s:\n", result.Query.Original, result.Palette, result.Size, result.Metric))
+	for i, m := range result.Matches {
+		color := m.Color
+		if m.Color != m.HEX {
+			color = fmt.Sprintf("%s (%s)", m.Color, m.HEX)
+		}
+		name := ""
+		if m.Name != "" && m.Name != m.HEX {
+			name = m.Name + ": "
+		}
+		builder.WriteString(fmt.Sprintf("  %d. %s%s  %.3f %s, %s\n", i+1, name, color, m.DeltaE, deltaELabel(result.Metric), m.Verdict))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
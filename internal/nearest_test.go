@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindNearestColor(t *testing.T) {
+	result, err := FindNearestBuiltinColor("#3c83f5", PaletteTailwind, 3, "", "hex", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Palette != "tailwind" || result.Metric != MetricDeltaEOK || len(result.Matches) != 3 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	first := result.Matches[0]
+	if first.Name != "blue-500" || first.HEX != "#3B82F6" || first.Verdict != VerdictIndistinguishable {
+		t.Errorf("expected blue-500 first, got %+v", first)
+	}
+	for i := 1; i < len(result.Matches); i++ {
+		if result.Matches[i].DeltaE < result.Matches[i-1].DeltaE {
+			t.Errorf("matches are not sorted: %+v", result.Matches)
+		}
+	}
+}
+
+func TestFindNearestColorMetrics(t *testing.T) {
+	// Both the k-d tree (deltaEOK) and the full scan (other metrics, custom
+	// palettes) must agree with a brute-force search
+	palette, err := GetBuiltinPalette(PaletteCSS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := "#7a3e9d"
+	q, _ := DetectFormat(query)
+	for _, metric := range deltaEMetrics {
+		distances := make([]float64, len(palette))
+		for i, e := range palette {
+			c, _ := DetectFormat(e.Color)
+			distances[i] = calculateDeltaE(metric, q.Linear, c.Linear)
+		}
+		sort.Float64s(distances)
+
+		builtin, err := FindNearestBuiltinColor(query, PaletteCSS, 3, metric, "hex", ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		custom, err := FindNearestColor(query, palette, 3, metric, "hex", ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, result := range []*NearestResult{builtin, custom} {
+			for i, m := range result.Matches {
+				if m.DeltaE != distances[i] {
+					t.Errorf("%s %s match %d: expected ΔE %.4f, got %.4f (%s)", result.Palette, metric, i, distances[i], m.DeltaE, m.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestFindNearestColorLargeCustomPalette(t *testing.T) {
+	// A palette above nearestIndexSize is indexed; the tree must return the
+	// same matches as a scan of the same palette
+	palette := make([]PaletteEntry, 3000)
+	seed := uint32(1)
+	for i := range palette {
+		seed = seed*1664525 + 1013904223
+		palette[i] = PaletteEntry{Color: fmt.Sprintf("#%06X", seed>>8)}
+	}
+	scanned, err := newPaletteIndex(palette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{"#3c83f5", "#000000", "#ffffff", "oklch(0.7 0.3 150)"} {
+		indexed, err := FindNearestColor(query, palette, 10, "", "hex", ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, _ := DetectFormat(query)
+		expected, err := findNearest(q, scanned, "custom", 10, "", "hex", ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range expected.Matches {
+			if indexed.Matches[i].HEX != expected.Matches[i].HEX || indexed.Matches[i].DeltaE != expected.Matches[i].DeltaE {
+				t.Errorf("%s match %d: indexed %+v, scanned %+v", query, i, indexed.Matches[i], expected.Matches[i])
+			}
+		}
+	}
+}
+
+func TestGetBuiltinIndex(t *testing.T) {
+	first, name, err := getBuiltinIndex("CSS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != PaletteCSS || first.tree == nil || len(first.colors) != len(namedColors) {
+		t.Fatalf("unexpected index %s with %d colors", name, len(first.colors))
+	}
+	// The index is built once and reused
+	if second, _, _ := getBuiltinIndex(PaletteCSS); second != first {
+		t.Error("expected the cached index")
+	}
+	if _, _, err := getBuiltinIndex("pantone"); err == nil {
+		t.Error("expected error for an unknown palette")
+	}
+}
+
+func TestFindNearestColorCustomPalette(t *testing.T) {
+	palette := ParsePaletteEntries([]string{"brand-primary: #2563eb", "brand-accent: #f97316", "#3b82f6"})
+	result, err := FindNearestColor("#3c83f5", palette, 2, MetricDeltaE2000, "rgb", ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Palette != "custom" || result.Size != 3 {
+		t.Errorf("unexpected palette %s (%d)", result.Palette, result.Size)
+	}
+	if result.Matches[0].Color != "rgb(59, 130, 246)" || result.Matches[1].Name != "brand-primary" {
+		t.Errorf("unexpected matches %+v", result.Matches)
+	}
+
+	text := FormatNearestColor(result)
+	if !strings.Contains(text, "2. brand-primary: rgb(37, 99, 235) (#2563EB)") || !strings.Contains(text, "ΔE2000") {
+		t.Errorf("unexpected output:\n%s", text)
+	}
+}
+
+func TestFindNearestColorErrors(t *testing.T) {
+	palette := ParsePaletteEntries([]string{"#ff0000", "#00ff00"})
+	tests := []struct {
+		name    string
+		color   string
+		palette []PaletteEntry
+		k       int
+		metric  DeltaEMetric
+	}{
+		{"invalid color", "notacolor", palette, 1, ""},
+		{"empty palette", "#ff0000", nil, 1, ""},
+		{"invalid palette color", "#ff0000", ParsePaletteEntries([]string{"x: nope"}), 1, ""},
+		{"count too high", "#ff0000", palette, MaxNearestCount + 1, ""},
+		{"negative count", "#ff0000", palette, -1, ""},
+		{"invalid metric", "#ff0000", palette, 1, "deltaE99"},
+	}
+	for _, tt := range tests {
+		if _, err := FindNearestColor(tt.color, tt.palette, tt.k, tt.metric, "hex", ConvertOptions{}); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if _, err := FindNearestBuiltinColor("#ff0000", "pantone", 1, "", "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for an unknown built-in palette")
+	}
+}
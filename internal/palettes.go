@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuiltinPalette names a palette shipped with the server
+type BuiltinPalette string
+
+const (
+	PaletteCSS      BuiltinPalette = "css"      // the 148 CSS named colors
+	PaletteTailwind BuiltinPalette = "tailwind" // Tailwind CSS v3 colors, 50-950
+	PaletteMaterial BuiltinPalette = "material" // Material Design 2014 colors, 50-900
+	PaletteWebSafe  BuiltinPalette = "web-safe" // the 216 web-safe colors
+)
+
+// PaletteEntry is a named color of a palette
+type PaletteEntry struct {
+	Name  string
+	Color string
+}
+
+// paletteFamily is a hue family of shades, lightest first
+type paletteFamily struct {
+	name   string
+	shades []string
+}
+
+// tailwindShades are the shade labels of the Tailwind palette
+var tailwindShades = []string{"50", "100", "200", "300", "400", "500", "600", "700", "800", "900", "950"}
+
+// tailwindFamilies holds the Tailwind CSS v3 default colors
+var tailwindFamilies = []paletteFamily{
+	{"slate", []string{"#F8FAFC", "#F1F5F9", "#E2E8F0", "#CBD5E1", "#94A3B8", "#64748B", "#475569", "#334155", "#1E293B", "#0F172A", "#020617"}},
+	{"gray", []string{"#F9FAFB", "#F3F4F6", "#E5E7EB", "#D1D5DB", "#9CA3AF", "#6B7280", "#4B5563", "#374151", "#1F2937", "#111827", "#030712"}},
+	{"zinc", []string{"#FAFAFA", "#F4F4F5", "#E4E4E7", "#D4D4D8", "#A1A1AA", "#71717A", "#52525B", "#3F3F46", "#27272A", "#18181B", "#09090B"}},
+	{"neutral", []string{"#FAFAFA", "#F5F5F5", "#E5E5E5", "#D4D4D4", "#A3A3A3", "#737373", "#525252", "#404040", "#262626", "#171717", "#0A0A0A"}},
+	{"stone", []string{"#FAFAF9", "#F5F5F4", "#E7E5E4", "#D6D3D1", "#A8A29E", "#78716C", "#57534E", "#44403C", "#292524", "#1C1917", "#0C0A09"}},
+	{"red", []string{"#FEF2F2", "#FEE2E2", "#FECACA", "#FCA5A5", "#F87171", "#EF4444", "#DC2626", "#B91C1C", "#991B1B", "#7F1D1D", "#450A0A"}},
+	{"orange", []string{"#FFF7ED", "#FFEDD5", "#FED7AA", "#FDBA74", "#FB923C", "#F97316", "#EA580C", "#C2410C", "#9A3412", "#7C2D12", "#431407"}},
+	{"amber", []string{"#FFFBEB", "#FEF3C7", "#FDE68A", "#FCD34D", "#FBBF24", "#F59E0B", "#D97706", "#B45309", "#92400E", "#78350F", "#451A03"}},
+	{"yellow", []string{"#FEFCE8", "#FEF9C3", "#FEF08A", "#FDE047", "#FACC15", "#EAB308", "#CA8A04", "#A16207", "#854D0E", "#713F12", "#422006"}},
+	{"lime", []string{"#F7FEE7", "#ECFCCB", "#D9F99D", "#BEF264", "#A3E635", "#84CC16", "#65A30D", "#4D7C0F", "#3F6212", "#365314", "#1A2E05"}},
+	{"green", []string{"#F0FDF4", "#DCFCE7", "#BBF7D0", "#86EFAC", "#4ADE80", "#22C55E", "#16A34A", "#15803D", "#166534", "#14532D", "#052E16"}},
+	{"emerald", []string{"#ECFDF5", "#D1FAE5", "#A7F3D0", "#6EE7B7", "#34D399", "#10B981", "#059669", "#047857", "#065F46", "#064E3B", "#022C22"}},
+	{"teal", []string{"#F0FDFA", "#CCFBF1", "#99F6E4", "#5EEAD4", "#2DD4BF", "#14B8A6", "#0D9488", "#0F766E", "#115E59", "#134E4A", "#042F2E"}},
+	{"cyan", []string{"#ECFEFF", "#CFFAFE", "#A5F3FC", "#67E8F9", "#22D3EE", "#06B6D4", "#0891B2", "#0E7490", "#155E75", "#164E63", "#083344"}},
+	{"sky", []string{"#F0F9FF", "#E0F2FE", "#BAE6FD", "#7DD3FC", "#38BDF8", "#0EA5E9", "#0284C7", "#0369A1", "#075985", "#0C4A6E", "#082F49"}},
+	{"blue", []string{"#EFF6FF", "#DBEAFE", "#BFDBFE", "#93C5FD", "#60A5FA", "#3B82F6", "#2563EB", "#1D4ED8", "#1E40AF", "#1E3A8A", "#172554"}},
+	{"indigo", []string{"#EEF2FF", "#E0E7FF", "#C7D2FE", "#A5B4FC", "#818CF8", "#6366F1", "#4F46E5", "#4338CA", "#3730A3", "#312E81", "#1E1B4B"}},
+	{"violet", []string{"#F5F3FF", "#EDE9FE", "#DDD6FE", "#C4B5FD", "#A78BFA", "#8B5CF6", "#7C3AED", "#6D28D9", "#5B21B6", "#4C1D95", "#2E1065"}},
+	{"purple", []string{"#FAF5FF", "#F3E8FF", "#E9D5FF", "#D8B4FE", "#C084FC", "#A855F7", "#9333EA", "#7E22CE", "#6B21A8", "#581C87", "#3B0764"}},
+	{"fuchsia", []string{"#FDF4FF", "#FAE8FF", "#F5D0FE", "#F0ABFC", "#E879F9", "#D946EF", "#C026D3", "#A21CAF", "#86198F", "#701A75", "#4A044E"}},
+	{"pink", []string{"#FDF2F8", "#FCE7F3", "#FBCFE8", "#F9A8D4", "#F472B6", "#EC4899", "#DB2777", "#BE185D", "#9D174D", "#831843", "#500724"}},
+	{"rose", []string{"#FFF1F2", "#FFE4E6", "#FECDD3", "#FDA4AF", "#FB7185", "#F43F5E", "#E11D48", "#BE123C", "#9F1239", "#881337", "#4C0519"}},
+}
+
+// materialShades are the shade labels of the Material palette
+var materialShades = []string{"50", "100", "200", "300", "400", "500", "600", "700", "800", "900"}
+
+// materialFamilies holds the Material Design 2014 colors (without accents)
+var materialFamilies = []paletteFamily{
+	{"red", []string{"#FFEBEE", "#FFCDD2", "#EF9A9A", "#E57373", "#EF5350", "#F44336", "#E53935", "#D32F2F", "#C62828", "#B71C1C"}},
+	{"pink", []string{"#FCE4EC", "#F8BBD0", "#F48FB1", "#F06292", "#EC407A", "#E91E63", "#D81B60", "#C2185B", "#AD1457", "#880E4F"}},
+	{"purple", []string{"#F3E5F5", "#E1BEE7", "#CE93D8", "#BA68C8", "#AB47BC", "#9C27B0", "#8E24AA", "#7B1FA2", "#6A1B9A", "#4A148C"}},
+	{"deep-purple", []string{"#EDE7F6", "#D1C4E9", "#B39DDB", "#9575CD", "#7E57C2", "#673AB7", "#5E35B1", "#512DA8", "#4527A0", "#311B92"}},
+	{"indigo", []string{"#E8EAF6", "#C5CAE9", "#9FA8DA", "#7986CB", "#5C6BC0", "#3F51B5", "#3949AB", "#303F9F", "#283593", "#1A237E"}},
+	{"blue", []string{"#E3F2FD", "#BBDEFB", "#90CAF9", "#64B5F6", "#42A5F5", "#2196F3", "#1E88E5", "#1976D2", "#1565C0", "#0D47A1"}},
+	{"light-blue", []string{"#E1F5FE", "#B3E5FC", "#81D4FA", "#4FC3F7", "#29B6F6", "#03A9F4", "#039BE5", "#0288D1", "#0277BD", "#01579B"}},
+	{"cyan", []string{"#E0F7FA", "#B2EBF2", "#80DEEA", "#4DD0E1", "#26C6DA", "#00BCD4", "#00ACC1", "#0097A7", "#00838F", "#006064"}},
+	{"teal", []string{"#E0F2F1", "#B2DFDB", "#80CBC4", "#4DB6AC", "#26A69A", "#009688", "#00897B", "#00796B", "#00695C", "#004D40"}},
+	{"green", []string{"#E8F5E9", "#C8E6C9", "#A5D6A7", "#81C784", "#66BB6A", "#4CAF50", "#43A047", "#388E3C", "#2E7D32", "#1B5E20"}},
+	{"light-green", []string{"#F1F8E9", "#DCEDC8", "#C5E1A5", "#AED581", "#9CCC65", "#8BC34A", "#7CB342", "#689F38", "#558B2F", "#33691E"}},
+	{"lime", []string{"#F9FBE7", "#F0F4C3", "#E6EE9C", "#DCE775", "#D4E157", "#CDDC39", "#C0CA33", "#AFB42B", "#9E9D24", "#827717"}},
+	{"yellow", []string{"#FFFDE7", "#FFF9C4", "#FFF59D", "#FFF176", "#FFEE58", "#FFEB3B", "#FDD835", "#FBC02D", "#F9A825", "#F57F17"}},
+	{"amber", []string{"#FFF8E1", "#FFECB3", "#FFE082", "#FFD54F", "#FFCA28", "#FFC107", "#FFB300", "#FFA000", "#FF8F00", "#FF6F00"}},
+	{"orange", []string{"#FFF3E0", "#FFE0B2", "#FFCC80", "#FFB74D", "#FFA726", "#FF9800", "#FB8C00", "#F57C00", "#EF6C00", "#E65100"}},
+	{"deep-orange", []string{"#FBE9E7", "#FFCCBC", "#FFAB91", "#FF8A65", "#FF7043", "#FF5722", "#F4511E", "#E64A19", "#D84315", "#BF360C"}},
+	{"brown", []string{"#EFEBE9", "#D7CCC8", "#BCAAA4", "#A1887F", "#8D6E63", "#795548", "#6D4C41", "#5D4037", "#4E342E", "#3E2723"}},
+	{"grey", []string{"#FAFAFA", "#F5F5F5", "#EEEEEE", "#E0E0E0", "#BDBDBD", "#9E9E9E", "#757575", "#616161", "#424242", "#212121"}},
+	{"blue-grey", []string{"#ECEFF1", "#CFD8DC", "#B0BEC5", "#90A4AE", "#78909C", "#607D8B", "#546E7A", "#455A64", "#37474F", "#263238"}},
+}
+
+// GetBuiltinPalettes returns the names of the built-in palettes
+func GetBuiltinPalettes() []string {
+	return []string{string(PaletteCSS), string(PaletteTailwind), string(PaletteMaterial), string(PaletteWebSafe)}
+}
+
+// GetBuiltinPalette returns the entries of a built-in palette, named like
+// "blue-500" for Tailwind and Material
+func GetBuiltinPalette(name BuiltinPalette) ([]PaletteEntry, error) {
+	switch BuiltinPalette(strings.ToLower(strings.TrimSpace(string(name)))) {
+	case PaletteCSS:
+		entries := make([]PaletteEntry, 0, len(namedColors))
+		for _, keyword := range sortedNamedColors {
+			entries = append(entries, PaletteEntry{Name: keyword, Color: namedColors[keyword]})
+		}
+		return entries, nil
+	case PaletteTailwind:
+		return familyEntries(tailwindFamilies, tailwindShades), nil
+	case PaletteMaterial:
+		return familyEntries(materialFamilies, materialShades), nil
+	case PaletteWebSafe:
+		steps := []int{0x00, 0x33, 0x66, 0x99, 0xCC, 0xFF}
+		entries := make([]PaletteEntry, 0, len(steps)*len(steps)*len(steps))
+		for _, r := range steps {
+			for _, g := range steps {
+				for _, b := range steps {
+					hex := fmt.Sprintf("#%02X%02X%02X", r, g, b)
+					entries = append(entries, PaletteEntry{Name: hex, Color: hex})
+				}
+			}
+		}
+		return entries, nil
+	default:
+		return nil, fmt.Errorf("invalid palette: %s (supported: %s)", name, strings.Join(GetBuiltinPalettes(), ", "))
+	}
+}
+
+// familyEntries flattens hue families into "family-shade" entries
+func familyEntries(families []paletteFamily, labels []string) []PaletteEntry {
+	entries := make([]PaletteEntry, 0, len(families)*len(labels))
+	for _, family := range families {
+		for i, hex := range family.shades {
+			entries = append(entries, PaletteEntry{Name: family.name + "-" + labels[i], Color: hex})
+		}
+	}
+	return entries
+}
+
+// ParsePaletteEntries reads palette entries given as colors or as "name: color";
+// unnamed entries have an empty name
+func ParsePaletteEntries(items []string) []PaletteEntry {
+	entries := make([]PaletteEntry, len(items))
+	for i, item := range items {
+		name, color, found := strings.Cut(item, ":")
+		if !found {
+			entries[i] = PaletteEntry{Color: strings.TrimSpace(item)}
+			continue
+		}
+		entries[i] = PaletteEntry{Name: strings.TrimSpace(name), Color: strings.TrimSpace(color)}
+	}
+	return entries
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestGetBuiltinPalette(t *testing.T) {
+	tests := []struct {
+		name  BuiltinPalette
+		size  int
+		entry PaletteEntry
+	}{
+		{PaletteCSS, 148, PaletteEntry{Name: "rebeccapurple", Color: "#663399"}},
+		{PaletteTailwind, 242, PaletteEntry{Name: "blue-500", Color: "#3B82F6"}},
+		{PaletteMaterial, 190, PaletteEntry{Name: "deep-purple-500", Color: "#673AB7"}},
+		{PaletteWebSafe, 216, PaletteEntry{Name: "#3366CC", Color: "#3366CC"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			entries, err := GetBuiltinPalette(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.size {
+				t.Errorf("expected %d entries, got %d", tt.size, len(entries))
+			}
+			found := false
+			for _, e := range entries {
+				if _, err := DetectFormat(e.Color); err != nil {
+					t.Errorf("%s: %v", e.Name, err)
+				}
+				if e == tt.entry {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected entry %+v", tt.entry)
+			}
+		})
+	}
+
+	if _, err := GetBuiltinPalette("pantone"); err == nil {
+		t.Error("expected error for an unknown palette")
+	}
+}
+
+func TestParsePaletteEntries(t *testing.T) {
+	entries := ParsePaletteEntries([]string{"brand-primary: #3b82f6", " #f97316 ", "accent:rgb(0, 128, 0)"})
+	expected := []PaletteEntry{
+		{Name: "brand-primary", Color: "#3b82f6"},
+		{Color: "#f97316"},
+		{Name: "accent", Color: "rgb(0, 128, 0)"},
+	}
+	for i, e := range expected {
+		if entries[i] != e {
+			t.Errorf("entry %d: expected %+v, got %+v", i, e, entries[i])
+		}
+	}
+}
@@ -0,0 +1,542 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// relativeColorPattern matches the start of CSS relative color syntax: oklch(from <color> l c h)
+var relativeColorPattern = regexp.MustCompile(`(?i)^(rgba?|hsla?|hwb|lab|lch|oklab|oklch|color)\s*\(\s*from\s`)
+
+// relativeSpace describes a color function that accepts relative color syntax
+type relativeSpace struct {
+	keywords [3]string  // channel keywords exposed to the channel expressions
+	percents [3]float64 // value a percentage resolves to at 100%; 0 when not allowed
+	hue      int        // index of the hue channel, -1 if there is none
+
+	// decompose returns the channel values of a color in this space
+	decompose func(data ColorData) [3]float64
+	// toRGB builds 0-255 RGB from channel values of an sRGB-based space
+	toRGB func(ch [3]float64) (r, g, b float64)
+	// compose builds linear sRGB from channel values of any other space
+	compose func(ch [3]float64) LinearColor
+}
+
+var relativeSpaces = map[ColorFormat]relativeSpace{
+	FormatRGB: {
+		keywords: [3]string{"r", "g", "b"},
+		percents: [3]float64{RGBMax, RGBMax, RGBMax},
+		hue:      -1,
+		decompose: func(data ColorData) [3]float64 {
+			return [3]float64{data.Color.R, data.Color.G, data.Color.B}
+		},
+		toRGB: func(ch [3]float64) (r, g, b float64) {
+			return clamp(ch[0], 0, RGBMax), clamp(ch[1], 0, RGBMax), clamp(ch[2], 0, RGBMax)
+		},
+	},
+	FormatHSL: {
+		keywords: [3]string{"h", "s", "l"},
+		percents: [3]float64{0, SaturationMax, LightnessMax},
+		hue:      0,
+		decompose: func(data ColorData) [3]float64 {
+			h, s, l := rgbToHSL(data.Color.R, data.Color.G, data.Color.B)
+			return [3]float64{h, s, l}
+		},
+		toRGB: func(ch [3]float64) (r, g, b float64) {
+			return hslToRGB(ch[0], clamp(ch[1], 0, SaturationMax), clamp(ch[2], 0, LightnessMax))
+		},
+	},
+	FormatHWB: {
+		keywords: [3]string{"h", "w", "b"},
+		percents: [3]float64{0, LightnessMax, LightnessMax},
+		hue:      0,
+		decompose: func(data ColorData) [3]float64 {
+			h, w, b := rgbToHWB(data.Color.R, data.Color.G, data.Color.B)
+			return [3]float64{h, w, b}
+		},
+		toRGB: func(ch [3]float64) (r, g, b float64) {
+			return hwbToRGB(ch[0], clamp(ch[1], 0, LightnessMax), clamp(ch[2], 0, LightnessMax))
+		},
+	},
+	FormatLAB: {
+		keywords: [3]string{"l", "a", "b"},
+		percents: [3]float64{LAB_L_Max, LAB_AB_Max, LAB_AB_Max},
+		hue:      -1,
+		decompose: func(data ColorData) [3]float64 {
+			l, a, b := linearToLAB(data.Linear.R, data.Linear.G, data.Linear.B)
+			return [3]float64{l, a, b}
+		},
+		compose: func(ch [3]float64) LinearColor {
+			r, g, b := labToLinear(clamp(ch[0], 0, LAB_L_Max), ch[1], ch[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	},
+	FormatLCH: {
+		keywords: [3]string{"l", "c", "h"},
+		percents: [3]float64{LAB_L_Max, LCH_C_Max, 0},
+		hue:      2,
+		decompose: func(data ColorData) [3]float64 {
+			l, c, h := linearToLCH(data.Linear.R, data.Linear.G, data.Linear.B)
+			return [3]float64{l, c, h}
+		},
+		compose: func(ch [3]float64) LinearColor {
+			r, g, b := lchToLinear(clamp(ch[0], 0, LAB_L_Max), math.Max(ch[1], 0), ch[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	},
+	FormatOKLab: {
+		keywords: [3]string{"l", "a", "b"},
+		percents: [3]float64{OKLCH_L_Max, OKLAB_AB_Max, OKLAB_AB_Max},
+		hue:      -1,
+		decompose: func(data ColorData) [3]float64 {
+			l, a, b := linearToOKLab(data.Linear.R, data.Linear.G, data.Linear.B)
+			return [3]float64{l, a, b}
+		},
+		compose: func(ch [3]float64) LinearColor {
+			r, g, b := oklabToLinear(clamp(ch[0], 0, OKLCH_L_Max), ch[1], ch[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	},
+	FormatOKLCH: {
+		keywords: [3]string{"l", "c", "h"},
+		percents: [3]float64{OKLCH_L_Max, OKLCH_C_Max, 0},
+		hue:      2,
+		decompose: func(data ColorData) [3]float64 {
+			l, c, h := linearToOKLCH(data.Linear.R, data.Linear.G, data.Linear.B)
+			return [3]float64{l, c, h}
+		},
+		compose: func(ch [3]float64) LinearColor {
+			r, g, b := oklchToLinear(clamp(ch[0], 0, OKLCH_L_Max), math.Max(ch[1], 0), ch[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	},
+}
+
+// predefinedRelativeSpace returns the relative syntax channels of a color() space
+func predefinedRelativeSpace(format ColorFormat) relativeSpace {
+	space := predefinedSpaces[format]
+	keywords := [3]string{"r", "g", "b"}
+	if format == FormatXYZD50 || format == FormatXYZD65 {
+		keywords = [3]string{"x", "y", "z"}
+	}
+	return relativeSpace{
+		keywords: keywords,
+		percents: [3]float64{1, 1, 1},
+		hue:      -1,
+		decompose: func(data ColorData) [3]float64 {
+			c1, c2, c3 := linearToPredefined(space, data.Linear.R, data.Linear.G, data.Linear.B)
+			return [3]float64{c1, c2, c3}
+		},
+		compose: func(ch [3]float64) LinearColor {
+			r, g, b := predefinedToLinear(space, ch[0], ch[1], ch[2])
+			return LinearColor{R: r, G: g, B: b}
+		},
+	}
+}
+
+// parseRelativeColor evaluates CSS relative color syntax such as
+// oklch(from #3b82f6 calc(l + 0.1) c h) or rgb(from red r g b / 50%)
+// The origin color may be any supported color, including another relative color
+func parseRelativeColor(input string) (ColorData, error) {
+	open := strings.Index(input, "(")
+	if !strings.HasSuffix(input, ")") {
+		return ColorData{}, fmt.Errorf("invalid relative color: %s", input)
+	}
+	name := strings.ToLower(strings.TrimSpace(input[:open]))
+	args, err := splitColorArgs(input[open+1 : len(input)-1])
+	if err != nil {
+		return ColorData{}, fmt.Errorf("invalid relative color %s: %w", input, err)
+	}
+	// args[0] is the "from" keyword
+	if len(args) < 2 {
+		return ColorData{}, fmt.Errorf("invalid relative color: %s (missing origin color)", input)
+	}
+
+	origin, err := DetectFormat(args[1])
+	if err != nil {
+		return ColorData{}, fmt.Errorf("invalid origin color in %s: %w", input, err)
+	}
+	args = args[2:]
+
+	var format ColorFormat
+	var space relativeSpace
+	switch name {
+	case "rgb", "rgba":
+		format = FormatRGB
+	case "hsl", "hsla":
+		format = FormatHSL
+	default:
+		format = ColorFormat(name)
+	}
+	if format == "color" {
+		if len(args) == 0 {
+			return ColorData{}, fmt.Errorf("invalid relative color: %s (missing color space)", input)
+		}
+		format = ColorFormat(strings.ToLower(args[0]))
+		if format == FormatXYZ {
+			format = FormatXYZD65
+		}
+		if _, ok := predefinedSpaces[format]; !ok {
+			return ColorData{}, fmt.Errorf("invalid relative color: %s (unknown color space %s)", input, args[0])
+		}
+		space = predefinedRelativeSpace(format)
+		args = args[1:]
+	} else {
+		space = relativeSpaces[format]
+	}
+
+	hasAlpha := false
+	alphaArg := ""
+	switch {
+	case len(args) == 3:
+	case len(args) == 5 && args[3] == "/":
+		hasAlpha = true
+		alphaArg = args[4]
+	default:
+		return ColorData{}, fmt.Errorf("invalid relative color: %s (expected 3 channels and an optional / alpha)", input)
+	}
+
+	// Channel keywords resolve to the origin color expressed in the target space
+	origChannels := space.decompose(origin)
+	vars := map[string]float64{"alpha": origin.Color.A}
+	for i, kw := range space.keywords {
+		vars[kw] = origChannels[i]
+	}
+
+	var channels [3]float64
+	for i := range channels {
+		v, err := evalColorExpression(args[i], vars, space.percents[i])
+		if err != nil {
+			return ColorData{}, fmt.Errorf("invalid %s channel in %s: %w", space.keywords[i], input, err)
+		}
+		if i == space.hue {
+			v = normalizeHue(v)
+		}
+		channels[i] = v
+	}
+
+	a := origin.Color.A
+	if hasAlpha {
+		a, err = evalColorExpression(alphaArg, vars, AlphaMax)
+		if err != nil {
+			return ColorData{}, fmt.Errorf("invalid alpha in %s: %w", input, err)
+		}
+	}
+	a = clamp(a, AlphaMin, AlphaMax)
+
+	if format == FormatRGB && (hasAlpha || name == "rgba") {
+		format = FormatRGBA
+	}
+	if format == FormatHSL && (hasAlpha || name == "hsla") {
+		format = FormatHSLA
+	}
+
+	var color Color
+	var linear LinearColor
+	if space.toRGB != nil {
+		r, g, b := space.toRGB(channels)
+		color = Color{R: r, G: g, B: b, A: a}
+		linear = color.Linear()
+	} else {
+		linear = space.compose(channels)
+		linear.A = a
+		color = linear.SRGB()
+	}
+
+	return ColorData{
+		Color:    color,
+		Linear:   linear,
+		Format:   format,
+		Original: input,
+	}, nil
+}
+
+// splitColorArgs splits the arguments of a color function on top-level
+// whitespace, keeping nested function calls together and "/" as its own token
+func splitColorArgs(s string) ([]string, error) {
+	var args []string
+	depth := 0
+	start := -1
+	flush := func(i int) {
+		if start >= 0 {
+			args = append(args, s[start:i])
+			start = -1
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '(':
+			depth++
+			if start < 0 {
+				start = i
+			}
+		case ch == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case depth == 0 && (ch == ' ' || ch == '\t' || ch == '\n'):
+			flush(i)
+		case depth == 0 && ch == '/':
+			flush(i)
+			args = append(args, "/")
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	flush(len(s))
+	return args, nil
+}
+
+// evalColorExpression evaluates a channel value: a number, percentage, angle,
+// channel keyword or calc() expression over those
+// percentRef is the value 100% resolves to; 0 means percentages are not allowed
+func evalColorExpression(expr string, vars map[string]float64, percentRef float64) (float64, error) {
+	p := &calcParser{input: strings.ToLower(strings.TrimSpace(expr)), vars: vars, percentRef: percentRef}
+	v, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q in %s", p.input[p.pos:], expr)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%s does not evaluate to a finite number", expr)
+	}
+	return v, nil
+}
+
+// angleUnits converts CSS angle units to degrees
+var angleUnits = map[string]float64{
+	"deg":  1,
+	"grad": FullCircle / 400,
+	"rad":  180 / math.Pi,
+	"turn": FullCircle,
+}
+
+// calcParser is a recursive descent parser for CSS calc() expressions
+type calcParser struct {
+	input      string
+	pos        int
+	vars       map[string]float64
+	percentRef float64
+}
+
+func (p *calcParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t' || p.input[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// parseSum parses: product (('+' | '-') product)*
+func (p *calcParser) parseSum() (float64, error) {
+	v, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return v, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		rhs, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+}
+
+// parseProduct parses: value (('*' | '/') value)*
+func (p *calcParser) parseProduct() (float64, error) {
+	v, err := p.parseValue()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			return v, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		rhs, err := p.parseValue()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			v *= rhs
+		} else {
+			if rhs == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			v /= rhs
+		}
+	}
+}
+
+// parseValue parses a number, keyword, parenthesized expression or math function
+func (p *calcParser) parseValue() (float64, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+
+	switch ch := p.input[p.pos]; {
+	case ch == '(':
+		p.pos++
+		v, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		return v, p.expect(')')
+	case ch == '-' || ch == '+':
+		p.pos++
+		v, err := p.parseValue()
+		if ch == '-' {
+			v = -v
+		}
+		return v, err
+	case ch == '.' || (ch >= '0' && ch <= '9'):
+		return p.parseNumber()
+	case ch >= 'a' && ch <= 'z':
+		return p.parseIdent()
+	default:
+		return 0, fmt.Errorf("unexpected %q", string(ch))
+	}
+}
+
+// parseNumber parses a number with an optional %, deg, grad, rad or turn unit
+func (p *calcParser) parseNumber() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+		p.pos++
+	}
+	// Exponent: 1e-3
+	if p.pos+1 < len(p.input) && p.input[p.pos] == 'e' &&
+		(p.input[p.pos+1] == '-' || p.input[p.pos+1] == '+' || (p.input[p.pos+1] >= '0' && p.input[p.pos+1] <= '9')) {
+		p.pos += 2
+		for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+			p.pos++
+		}
+	}
+	v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", p.input[start:p.pos])
+	}
+
+	if p.pos < len(p.input) && p.input[p.pos] == '%' {
+		p.pos++
+		if p.percentRef == 0 {
+			return 0, fmt.Errorf("percentages are not allowed here")
+		}
+		return v / 100 * p.percentRef, nil
+	}
+
+	unitStart := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= 'a' && p.input[p.pos] <= 'z' {
+		p.pos++
+	}
+	if unit := p.input[unitStart:p.pos]; unit != "" {
+		scale, ok := angleUnits[unit]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", unit)
+		}
+		return v * scale, nil
+	}
+	return v, nil
+}
+
+// parseIdent parses a channel keyword, constant or math function call
+func (p *calcParser) parseIdent() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && ((p.input[p.pos] >= 'a' && p.input[p.pos] <= 'z') || p.input[p.pos] == '-') {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		return p.parseFunction(name)
+	}
+
+	switch name {
+	case "none":
+		return 0, nil
+	case "pi":
+		return math.Pi, nil
+	case "e":
+		return math.E, nil
+	}
+	if v, ok := p.vars[name]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown keyword %q", name)
+}
+
+// parseFunction parses the arguments of calc(), min(), max() and clamp()
+func (p *calcParser) parseFunction(name string) (float64, error) {
+	var args []float64
+	for {
+		v, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, v)
+		p.skipSpace()
+		if p.pos < len(p.input) && p.input[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if err := p.expect(')'); err != nil {
+			return 0, err
+		}
+		break
+	}
+
+	switch {
+	case name == "calc" && len(args) == 1:
+		return args[0], nil
+	case name == "min":
+		v := args[0]
+		for _, a := range args[1:] {
+			v = math.Min(v, a)
+		}
+		return v, nil
+	case name == "max":
+		v := args[0]
+		for _, a := range args[1:] {
+			v = math.Max(v, a)
+		}
+		return v, nil
+	case name == "clamp" && len(args) == 3:
+		return math.Max(args[0], math.Min(args[1], args[2])), nil
+	default:
+		return 0, fmt.Errorf("unsupported function %s() with %d arguments", name, len(args))
+	}
+}
+
+func (p *calcParser) expect(ch byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != ch {
+		return fmt.Errorf("expected %q", string(ch))
+	}
+	p.pos++
+	return nil
+}
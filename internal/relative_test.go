@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRelativeColorSyntax(t *testing.T) {
+	tests := []struct {
+		input    string
+		format   ColorFormat
+		expected string // hex of the resolved color
+	}{
+		{"rgb(from red r g b / 50%)", FormatRGBA, "#FF000080"},
+		{"rgb(from rgb(from red r g b) b g r)", FormatRGB, "#0000FF"},
+		{"rgb(from red calc(r / 2) min(g, 10) clamp(0, b + 300, 255))", FormatRGB, "#8000FF"},
+		{"hsl(from red calc(h + 120) s l)", FormatHSL, "#00FF00"},
+		{"hwb(from blue h 20% b)", FormatHWB, "#3333FF"},
+		{"oklch(from #3b82f6 calc(l + 0.1) c h)", FormatOKLCH, "#5AA2FF"},
+		{"OKLCH(from #3b82f6 l c h)", FormatOKLCH, "#3B82F6"},
+		{"oklch(from red l c h / calc(alpha - 0.25))", FormatOKLCH, "#FF0000BF"},
+		{"oklab(from #808080 l 0.1 b / alpha)", FormatOKLab, "#B1667E"},
+		{"lab(from #FF0000 l a b)", FormatLAB, "#FF0000"},
+		{"lch(from red l c calc(h + 180deg))", FormatLCH, "#00A3FB"},
+		{"color(from red display-p3 r g b)", FormatDisplayP3, "#FF0000"},
+		{"color(from red xyz x y z)", FormatXYZD65, "#FF0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			data, err := DetectFormat(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.Format != tt.format {
+				t.Errorf("expected format %s, got %s", tt.format, data.Format)
+			}
+			result, err := Convert(tt.input, "hex", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestRelativeColorKeepsWideGamut checks that channels are read from the unclamped origin
+func TestRelativeColorKeepsWideGamut(t *testing.T) {
+	data, err := DetectFormat("color(from color(display-p3 0 1 0) display-p3 r g b)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p3, _, _ := linearToPredefined(predefinedSpaces[FormatDisplayP3], data.Linear.R, data.Linear.G, data.Linear.B)
+	if math.Abs(p3) > 1e-9 {
+		t.Errorf("expected red channel 0, got %f", p3)
+	}
+	if inGamut(data.Linear, predefinedSpaces[FormatSRGB]) {
+		t.Error("expected the result to stay outside sRGB")
+	}
+}
+
+func TestRelativeColorErrors(t *testing.T) {
+	inputs := []string{
+		"oklch(from red foo c h)",
+		"rgb(from red r g)",
+		"rgb(from notacolor r g b)",
+		"color(from red cmyk r g b)",
+		"oklch(from red l c 50%)",
+		"rgb(from red calc(r / 0) g b)",
+		"rgb(from red calc(r + ) g b)",
+		"rgb(from red r g b / )",
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := DetectFormat(input); err == nil {
+				t.Errorf("expected error for %s", input)
+			}
+		})
+	}
+}
+
+func TestEvalColorExpression(t *testing.T) {
+	vars := map[string]float64{"l": 0.5, "c": 0.1, "h": 200}
+	tests := []struct {
+		expr     string
+		expected float64
+	}{
+		{"l", 0.5},
+		{"0.25", 0.25},
+		{"50%", 0.2},
+		{"calc(l + 0.1)", 0.6},
+		{"calc(l * 2 - c)", 0.9},
+		{"calc((l + c) / 2)", 0.3},
+		{"calc(h - 0.5turn)", 20},
+		{"calc(-1 * c)", -0.1},
+		{"max(c, 0.3)", 0.3},
+		{"calc(1e-1 + l)", 0.6},
+		{"none", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			v, err := evalColorExpression(tt.expr, vars, 0.4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(v-tt.expected) > 1e-9 {
+				t.Errorf("expected %f, got %f", tt.expected, v)
+			}
+		})
+	}
+}
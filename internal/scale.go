@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Tonal scale defaults and limits
+const (
+	DefaultScaleSteps = 11
+	MinScaleSteps     = 3
+	MaxScaleSteps     = 21
+
+	// OKLCH lightness of the lightest and darkest steps, close to Tailwind's 50 and 950
+	scaleLightest = 0.97
+	scaleDarkest  = 0.27
+	// scaleChromaFloor is the share of the base chroma kept at the lightest and darkest steps
+	scaleChromaFloor = 0.2
+)
+
+// tailwindScaleLabels names the steps of an 11-step scale
+var tailwindScaleLabels = []int{50, 100, 200, 300, 400, 500, 600, 700, 800, 900, 950}
+
+// ScaleStep is one shade of a tonal scale
+type ScaleStep struct {
+	Label     int     // 50-950
+	Color     string  // in the requested format
+	HEX       string  // sRGB hex of the same color
+	Lightness float64 // OKLCH lightness after gamut mapping
+	Chroma    float64 // OKLCH chroma after gamut mapping
+	Hue       float64 // OKLCH hue
+	DeltaE    float64 // OKLCH ΔE from the previous step (0 for the first step)
+	IsBase    bool    // the base color sits at this step
+}
+
+// ScaleResult holds a tonal scale built from a base color
+type ScaleResult struct {
+	Base      ColorData
+	Format    string
+	Steps     []ScaleStep
+	MinDeltaE float64 // smallest ΔE between neighboring steps
+	MaxDeltaE float64 // largest ΔE between neighboring steps
+}
+
+// GenerateTonalScale builds a light-to-dark shade scale from a base color.
+// Lightness targets are spaced evenly in OKLCH between the lightest and darkest
+// steps, the base is placed at the closest step and the targets on each side are
+// respaced around it. Chroma tapers toward both ends and every step is
+// gamut-mapped into sRGB with the CSS Color 4 algorithm.
+func GenerateTonalScale(color string, steps int, targetFormat string, opts ConvertOptions) (*ScaleResult, error) {
+	if steps == 0 {
+		steps = DefaultScaleSteps
+	}
+	if steps < MinScaleSteps || steps > MaxScaleSteps {
+		return nil, fmt.Errorf("steps must be between %d and %d, got %d", MinScaleSteps, MaxScaleSteps, steps)
+	}
+
+	data, err := DetectFormat(color)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %w", err)
+	}
+	l, c, h := linearToOKLCH(data.Linear.R, data.Linear.G, data.Linear.B)
+
+	lightness, base := scaleLightness(l, steps)
+	labels := scaleLabels(steps)
+
+	result := &ScaleResult{Base: data, Format: targetFormat}
+	var prev Color
+	for i, target := range lightness {
+		var lc LinearColor
+		if i == base {
+			lc = sRGBGamutMap(data.Linear)
+		} else {
+			r, g, b := oklchToLinear(target, c*scaleChromaTaper(target, l), h)
+			lc = sRGBGamutMap(LinearColor{R: r, G: g, B: b, A: data.Linear.A})
+		}
+		srgb := lc.SRGB()
+
+		formatted, err := FormatColor(ColorData{Color: srgb, Linear: lc}, targetFormat, opts)
+		if err != nil {
+			return nil, err
+		}
+		ml, mc, _ := linearToOKLCH(lc.R, lc.G, lc.B)
+		step := ScaleStep{
+			Label:     labels[i],
+			Color:     formatted,
+			HEX:       formatHEX(srgb.R, srgb.G, srgb.B, srgb.A),
+			Lightness: ml,
+			Chroma:    mc,
+			Hue:       h,
+			IsBase:    i == base,
+		}
+		if i > 0 {
+			step.DeltaE = calculateOKLCHDeltaE(prev, srgb)
+			if i == 1 || step.DeltaE < result.MinDeltaE {
+				result.MinDeltaE = step.DeltaE
+			}
+			result.MaxDeltaE = math.Max(result.MaxDeltaE, step.DeltaE)
+		}
+		result.Steps = append(result.Steps, step)
+		prev = srgb
+	}
+
+	return result, nil
+}
+
+// scaleLightness returns the lightness target of each step, lightest first, and
+// the index of the step the base lightness is placed at
+func scaleLightness(base float64, steps int) ([]float64, int) {
+	targets := make([]float64, steps)
+	closest := 0
+	for i := range targets {
+		targets[i] = scaleLightest + (scaleDarkest-scaleLightest)*float64(i)/float64(steps-1)
+		if math.Abs(targets[i]-base) < math.Abs(targets[closest]-base) {
+			closest = i
+		}
+	}
+
+	// Respace each side evenly between its end and the base so the scale stays monotonic
+	lightest := math.Max(scaleLightest, base)
+	darkest := math.Min(scaleDarkest, base)
+	for i := range targets {
+		switch {
+		case i < closest:
+			targets[i] = lightest + (base-lightest)*float64(i)/float64(closest)
+		case i > closest:
+			targets[i] = base + (darkest-base)*float64(i-closest)/float64(steps-1-closest)
+		default:
+			targets[i] = base
+		}
+	}
+	return targets, closest
+}
+
+// scaleChromaTaper scales the base chroma down as lightness moves away from the
+// base toward white or black
+func scaleChromaTaper(target, base float64) float64 {
+	end := scaleDarkest
+	if target > base {
+		end = scaleLightest
+	}
+	if math.Abs(end-base) < 1e-9 {
+		return 1
+	}
+	t := clamp((target-base)/(end-base), 0, 1)
+	return 1 - (1-scaleChromaFloor)*t*t
+}
+
+// scaleLabels returns Tailwind's 50-950 names for 11 steps, or evenly spread
+// labels rounded to 10 for other step counts
+func scaleLabels(steps int) []int {
+	if steps == len(tailwindScaleLabels) {
+		return tailwindScaleLabels
+	}
+	labels := make([]int, steps)
+	for i := range labels {
+		labels[i] = int(math.Round((50+900*float64(i)/float64(steps-1))/10)) * 10
+	}
+	labels[0], labels[steps-1] = 50, 950
+	return labels
+}
+
+// FormatTonalScale formats a tonal scale as text
+func FormatTonalScale(result *ScaleResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Tonal scale: %s (%s), %d steps\n", result.Base.Original, result.Base.Format, len(result.Steps)))
+	builder.WriteString("Lightness spaced evenly in OKLCH, gamut-mapped to sRGB\n\n")
+
+	for i, step := range result.Steps {
+		color := step.Color
+		if step.Color != step.HEX {
+			color = fmt.Sprintf("%s (%s)", step.Color, step.HEX)
+		}
+		builder.WriteString(fmt.Sprintf("  %3d: %s  L %.3f C %.4f", step.Label, color, step.Lightness, step.Chroma))
+		if i > 0 {
+			builder.WriteString(fmt.Sprintf("  ΔE %.4f", step.DeltaE))
+		}
+		if step.IsBase {
+			builder.WriteString("  ← base")
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("\nNeighbor ΔE: min %.4f, max %.4f", result.MinDeltaE, result.MaxDeltaE))
+	return builder.String()
+}
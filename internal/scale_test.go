@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestGenerateTonalScale(t *testing.T) {
+	result, err := GenerateTonalScale("#3b82f6", 0, "hex", ConvertOptions{PreserveAlpha: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Steps) != DefaultScaleSteps {
+		t.Fatalf("expected %d steps, got %d", DefaultScaleSteps, len(result.Steps))
+	}
+
+	expected := map[int]string{50: "#E8F6FF", 500: "#3B82F6", 950: "#1B2739"}
+	for _, step := range result.Steps {
+		if hex, ok := expected[step.Label]; ok && step.Color != hex {
+			t.Errorf("step %d: expected %s, got %s", step.Label, hex, step.Color)
+		}
+		if step.IsBase != (step.Label == 500) {
+			t.Errorf("step %d: unexpected base flag %v", step.Label, step.IsBase)
+		}
+	}
+
+	for i := 1; i < len(result.Steps); i++ {
+		if result.Steps[i].Lightness >= result.Steps[i-1].Lightness {
+			t.Errorf("lightness is not decreasing at step %d", result.Steps[i].Label)
+		}
+		if result.Steps[i].DeltaE <= 0 {
+			t.Errorf("step %d: expected a positive ΔE", result.Steps[i].Label)
+		}
+	}
+	// Chroma tapers toward both ends
+	if result.Steps[0].Chroma >= result.Steps[5].Chroma || result.Steps[10].Chroma >= result.Steps[5].Chroma {
+		t.Errorf("expected chroma to taper toward the ends")
+	}
+	if result.MinDeltaE > result.MaxDeltaE || result.MinDeltaE <= 0 {
+		t.Errorf("unexpected neighbor ΔE range %.4f-%.4f", result.MinDeltaE, result.MaxDeltaE)
+	}
+}
+
+func TestGenerateTonalScaleBasePlacement(t *testing.T) {
+	tests := []struct {
+		color string
+		label int
+	}{
+		{"#facc15", 200},
+		{"#111827", 950},
+		{"#ffffff", 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			result, err := GenerateTonalScale(tt.color, 0, "hex", ConvertOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, step := range result.Steps {
+				if step.IsBase && step.Label != tt.label {
+					t.Errorf("expected base at %d, got %d", tt.label, step.Label)
+				}
+			}
+		})
+	}
+}
+
+func TestScaleLabels(t *testing.T) {
+	labels := scaleLabels(5)
+	expected := []int{50, 280, 500, 730, 950}
+	for i := range expected {
+		if labels[i] != expected[i] {
+			t.Errorf("expected labels %v, got %v", expected, labels)
+			break
+		}
+	}
+}
+
+func TestGenerateTonalScaleErrors(t *testing.T) {
+	if _, err := GenerateTonalScale("#3b82f6", 2, "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for too few steps")
+	}
+	if _, err := GenerateTonalScale("#3b82f6", MaxScaleSteps+1, "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for too many steps")
+	}
+	if _, err := GenerateTonalScale("nope", 0, "hex", ConvertOptions{}); err == nil {
+		t.Error("expected error for invalid color")
+	}
+}
@@ -23,6 +23,20 @@ const (
 	FormatXYZ   ColorFormat = "xyz"
 	FormatHWB   ColorFormat = "hwb"
 	FormatCMYK  ColorFormat = "cmyk"
+	FormatNamed ColorFormat = "named"
+	FormatOKLab ColorFormat = "oklab"
+	FormatLCH   ColorFormat = "lch"
+	FormatHCT   ColorFormat = "hct" // Material Design 3 hue, chroma, tone
+
+	// Predefined color spaces of the CSS color() function
+	FormatSRGB        ColorFormat = "srgb"
+	FormatSRGBLinear  ColorFormat = "srgb-linear"
+	FormatDisplayP3   ColorFormat = "display-p3"
+	FormatRec2020     ColorFormat = "rec2020"
+	FormatA98RGB      ColorFormat = "a98-rgb"
+	FormatProPhotoRGB ColorFormat = "prophoto-rgb"
+	FormatXYZD50      ColorFormat = "xyz-d50"
+	FormatXYZD65      ColorFormat = "xyz-d65"
 )
 
 // Color represents a color in RGB format with optional alpha
@@ -31,30 +45,84 @@ type Color struct {
 	A       float64 // 0-1, 1 if no alpha
 }
 
+// LinearColor represents a color in linear-light sRGB with optional alpha
+// Channels are not clamped: values outside 0-1 describe colors outside the
+// sRGB gamut, which keeps conversions between wide color spaces lossless
+type LinearColor struct {
+	R, G, B float64 // 0-1 inside the sRGB gamut
+	A       float64 // 0-1, 1 if no alpha
+}
+
+// Linear returns the color in linear-light sRGB
+func (c Color) Linear() LinearColor {
+	return LinearColor{
+		R: srgbInverseGamma(c.R / RGBMax),
+		G: srgbInverseGamma(c.G / RGBMax),
+		B: srgbInverseGamma(c.B / RGBMax),
+		A: c.A,
+	}
+}
+
+// SRGB returns the color gamma-encoded in sRGB, with channels clamped to 0-255
+func (lc LinearColor) SRGB() Color {
+	r, g, b := linearToRGB(lc.R, lc.G, lc.B)
+	return Color{R: r, G: g, B: b, A: lc.A}
+}
+
 // ColorData represents parsed color with format information
+// Color is always within sRGB; Linear keeps the unclamped value of wide-gamut input
 type ColorData struct {
 	Color    Color
+	Linear   LinearColor
 	Format   ColorFormat
 	Original string
 }
 
+// hueToken matches a hue angle with an optional CSS unit (deg, grad, rad, turn)
+const hueToken = `(-?[0-9]*\.?[0-9]+(?:deg|grad|rad|turn)?)`
+
+// alphaToken matches an optional "/ A" alpha of the space-separated syntax, and
+// legacyAlphaToken the ", A" alpha of the comma syntax; both capture the value
+// and an optional percent sign
+const (
+	alphaToken       = `(?:/\s*([0-9]*\.?[0-9]+)(%?)\s*)?`
+	legacyAlphaToken = `(?:,\s*([0-9]*\.?[0-9]+)(%?)\s*)?`
+)
+
 // Regex patterns for color format detection
 var (
-	hexPattern   = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
-	rgbPattern   = regexp.MustCompile(`^rgba?\s*\(\s*([0-9]+\.?[0-9]*)(%?)\s*,\s*([0-9]+\.?[0-9]*)(%?)\s*,\s*([0-9]+\.?[0-9]*)(%?)\s*(?:,\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	hslPattern   = regexp.MustCompile(`^hsla?\s*\(\s*([0-9]+\.?[0-9]*)\s*,\s*([0-9]+\.?[0-9]*)%\s*,\s*([0-9]+\.?[0-9]*)%\s*(?:,\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	hsbPattern   = regexp.MustCompile(`(?i)^hs[bcv]\s*\(\s*([0-9]+\.?[0-9]*)\s*,\s*([0-9]+\.?[0-9]*)%\s*,\s*([0-9]+\.?[0-9]*)%\s*(?:,\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	oklchPattern = regexp.MustCompile(`(?i)^oklch\s*\(\s*([0-9]*\.?[0-9]+)(%?)\s+([0-9]*\.?[0-9]+)(?:\s+([0-9]*\.?[0-9]+))?\s*(?:/\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	labPattern   = regexp.MustCompile(`(?i)^lab\s*\(\s*([0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s*(?:/\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	xyzPattern   = regexp.MustCompile(`(?i)^xyz\s*\(\s*(-?[0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s*(?:/\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	hwbPattern   = regexp.MustCompile(`(?i)^hwb\s*\(\s*([0-9]+\.?[0-9]*)\s+([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s*(?:/\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
-	cmykPattern  = regexp.MustCompile(`(?i)^cmyk\s*\(\s*([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s*(?:/\s*([0-9]*\.?[0-9]+)\s*)?\)$`)
+	hexPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	rgbPattern = regexp.MustCompile(`^rgba?\s*\(\s*([0-9]+\.?[0-9]*)(%?)\s*,\s*([0-9]+\.?[0-9]*)(%?)\s*,\s*([0-9]+\.?[0-9]*)(%?)\s*` + legacyAlphaToken + `\)$`)
+	hslPattern = regexp.MustCompile(`(?i)^hsla?\s*\(\s*` + hueToken + `\s*,\s*([0-9]+\.?[0-9]*)%\s*,\s*([0-9]+\.?[0-9]*)%\s*` + legacyAlphaToken + `\)$`)
+	// CSS Color 4 space-separated syntax: rgb(255 0 0 / 50%), hsl(120deg 100% 50% / .3)
+	rgbModernPattern = regexp.MustCompile(`(?i)^rgba?\s*\(\s*([0-9]*\.?[0-9]+)(%?)\s+([0-9]*\.?[0-9]+)(%?)\s+([0-9]*\.?[0-9]+)(%?)\s*` + alphaToken + `\)$`)
+	hslModernPattern = regexp.MustCompile(`(?i)^hsla?\s*\(\s*` + hueToken + `\s+([0-9]*\.?[0-9]+)%?\s+([0-9]*\.?[0-9]+)%?\s*` + alphaToken + `\)$`)
+	hsbPattern       = regexp.MustCompile(`(?i)^hs[bcv]\s*\(\s*` + hueToken + `\s*,\s*([0-9]+\.?[0-9]*)%\s*,\s*([0-9]+\.?[0-9]*)%\s*` + legacyAlphaToken + `\)$`)
+	oklchPattern     = regexp.MustCompile(`(?i)^oklch\s*\(\s*([0-9]*\.?[0-9]+)(%?)\s+([0-9]*\.?[0-9]+)(?:\s+` + hueToken + `)?\s*` + alphaToken + `\)$`)
+	oklabPattern     = regexp.MustCompile(`(?i)^oklab\s*\(\s*([0-9]*\.?[0-9]+)(%?)\s+(-?[0-9]*\.?[0-9]+)(%?)\s+(-?[0-9]*\.?[0-9]+)(%?)\s*` + alphaToken + `\)$`)
+	lchPattern       = regexp.MustCompile(`(?i)^lch\s*\(\s*([0-9]*\.?[0-9]+)(%?)\s+([0-9]*\.?[0-9]+)(%?)\s+` + hueToken + `\s*` + alphaToken + `\)$`)
+	labPattern       = regexp.MustCompile(`(?i)^lab\s*\(\s*([0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s*` + alphaToken + `\)$`)
+	xyzPattern       = regexp.MustCompile(`(?i)^xyz\s*\(\s*(-?[0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s+(-?[0-9]*\.?[0-9]+)\s*` + alphaToken + `\)$`)
+	hwbPattern       = regexp.MustCompile(`(?i)^hwb\s*\(\s*` + hueToken + `\s+([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s*` + alphaToken + `\)$`)
+	hctPattern       = regexp.MustCompile(`(?i)^hct\s*\(\s*` + hueToken + `\s+([0-9]*\.?[0-9]+)\s+([0-9]*\.?[0-9]+)\s*` + alphaToken + `\)$`)
+	cmykPattern      = regexp.MustCompile(`(?i)^cmyk\s*\(\s*([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s+([0-9]+\.?[0-9]*)%\s*` + alphaToken + `\)$`)
+	colorPattern     = regexp.MustCompile(`(?i)^color\s*\(\s*(srgb-linear|srgb|display-p3|rec2020|a98-rgb|prophoto-rgb|xyz-d50|xyz-d65|xyz)\s+(-?[0-9]*\.?[0-9]+)(%?)\s+(-?[0-9]*\.?[0-9]+)(%?)\s+(-?[0-9]*\.?[0-9]+)(%?)\s*` + alphaToken + `\)$`)
 )
 
 // DetectFormat detects the color format from the input string
 func DetectFormat(input string) (ColorData, error) {
 	input = strings.TrimSpace(input)
 
+	// Try CSS relative color syntax, e.g. oklch(from #3b82f6 calc(l + 0.1) c h)
+	if relativeColorPattern.MatchString(input) {
+		return parseRelativeColor(input)
+	}
+
+	// Try CSS color-mix(), e.g. color-mix(in oklch, #f00 30%, blue)
+	if colorMixPattern.MatchString(input) {
+		return parseColorMix(input)
+	}
+
 	// Try HEX
 	if hexPattern.MatchString(input) {
 		color, err := parseHEX(input)
@@ -63,13 +131,14 @@ func DetectFormat(input string) (ColorData, error) {
 		}
 		return ColorData{
 			Color:    color,
+			Linear:   color.Linear(),
 			Format:   FormatHEX,
 			Original: input,
 		}, nil
 	}
 
-	// Try RGB/RGBA (both numeric and percentage)
-	if rgbPattern.MatchString(input) {
+	// Try RGB/RGBA (both numeric and percentage, legacy and modern syntax)
+	if rgbPattern.MatchString(input) || rgbModernPattern.MatchString(input) {
 		color, hasAlpha, err := parseRGB(input)
 		if err != nil {
 			return ColorData{}, err
@@ -80,13 +149,14 @@ func DetectFormat(input string) (ColorData, error) {
 		}
 		return ColorData{
 			Color:    color,
+			Linear:   color.Linear(),
 			Format:   format,
 			Original: input,
 		}, nil
 	}
 
-	// Try HSL/HSLA
-	if hslPattern.MatchString(input) {
+	// Try HSL/HSLA (legacy and modern syntax)
+	if hslPattern.MatchString(input) || hslModernPattern.MatchString(input) {
 		color, hasAlpha, err := parseHSL(input)
 		if err != nil {
 			return ColorData{}, err
@@ -97,6 +167,7 @@ func DetectFormat(input string) (ColorData, error) {
 		}
 		return ColorData{
 			Color:    color,
+			Linear:   color.Linear(),
 			Format:   format,
 			Original: input,
 		}, nil
@@ -116,6 +187,7 @@ func DetectFormat(input string) (ColorData, error) {
 		}
 		return ColorData{
 			Color:    color,
+			Linear:   color.Linear(),
 			Format:   format,
 			Original: input,
 		}, nil
@@ -123,38 +195,69 @@ func DetectFormat(input string) (ColorData, error) {
 
 	// Try OKLCH
 	if oklchPattern.MatchString(input) {
-		color, err := parseOKLCH(input)
+		linear, err := parseOKLCH(input)
 		if err != nil {
 			return ColorData{}, err
 		}
 		return ColorData{
-			Color:    color,
+			Color:    linear.SRGB(),
+			Linear:   linear,
 			Format:   FormatOKLCH,
 			Original: input,
 		}, nil
 	}
 
+	// Try OKLab
+	if oklabPattern.MatchString(input) {
+		linear, err := parseOKLab(input)
+		if err != nil {
+			return ColorData{}, err
+		}
+		return ColorData{
+			Color:    linear.SRGB(),
+			Linear:   linear,
+			Format:   FormatOKLab,
+			Original: input,
+		}, nil
+	}
+
 	// Try LAB
 	if labPattern.MatchString(input) {
-		color, err := parseLAB(input)
+		linear, err := parseLAB(input)
 		if err != nil {
 			return ColorData{}, err
 		}
 		return ColorData{
-			Color:    color,
+			Color:    linear.SRGB(),
+			Linear:   linear,
 			Format:   FormatLAB,
 			Original: input,
 		}, nil
 	}
 
+	// Try LCH
+	if lchPattern.MatchString(input) {
+		linear, err := parseLCH(input)
+		if err != nil {
+			return ColorData{}, err
+		}
+		return ColorData{
+			Color:    linear.SRGB(),
+			Linear:   linear,
+			Format:   FormatLCH,
+			Original: input,
+		}, nil
+	}
+
 	// Try XYZ
 	if xyzPattern.MatchString(input) {
-		color, err := parseXYZ(input)
+		linear, err := parseXYZ(input)
 		if err != nil {
 			return ColorData{}, err
 		}
 		return ColorData{
-			Color:    color,
+			Color:    linear.SRGB(),
+			Linear:   linear,
 			Format:   FormatXYZ,
 			Original: input,
 		}, nil
@@ -168,11 +271,26 @@ func DetectFormat(input string) (ColorData, error) {
 		}
 		return ColorData{
 			Color:    color,
+			Linear:   color.Linear(),
 			Format:   FormatHWB,
 			Original: input,
 		}, nil
 	}
 
+	// Try HCT
+	if hctPattern.MatchString(input) {
+		linear, err := parseHCT(input)
+		if err != nil {
+			return ColorData{}, err
+		}
+		return ColorData{
+			Color:    linear.SRGB(),
+			Linear:   linear,
+			Format:   FormatHCT,
+			Original: input,
+		}, nil
+	}
+
 	// Try CMYK
 	if cmykPattern.MatchString(input) {
 		color, err := parseCMYK(input)
@@ -181,11 +299,40 @@ func DetectFormat(input string) (ColorData, error) {
 		}
 		return ColorData{
 			Color:    color,
+			Linear:   color.Linear(),
 			Format:   FormatCMYK,
 			Original: input,
 		}, nil
 	}
 
+	// Try CSS color() with a predefined color space
+	if colorPattern.MatchString(input) {
+		linear, format, err := parseColorFunction(input)
+		if err != nil {
+			return ColorData{}, err
+		}
+		return ColorData{
+			Color:    linear.SRGB(),
+			Linear:   linear,
+			Format:   format,
+			Original: input,
+		}, nil
+	}
+
+	// Try CSS named color / transparent
+	if isNamedColor(input) {
+		color, err := parseNamed(input)
+		if err != nil {
+			return ColorData{}, err
+		}
+		return ColorData{
+			Color:    color,
+			Linear:   color.Linear(),
+			Format:   FormatNamed,
+			Original: input,
+		}, nil
+	}
+
 	return ColorData{}, fmt.Errorf("unrecognized color format: %s", input)
 }
 
@@ -248,6 +395,9 @@ func parseHexByte(s string) int {
 // parseRGB parses an RGB/RGBA color string
 func parseRGB(input string) (Color, bool, error) {
 	matches := rgbPattern.FindStringSubmatch(input)
+	if matches == nil {
+		matches = rgbModernPattern.FindStringSubmatch(input)
+	}
 	if matches == nil {
 		return Color{}, false, fmt.Errorf("invalid RGB format: %s", input)
 	}
@@ -271,13 +421,11 @@ func parseRGB(input string) (Color, bool, error) {
 	g := gChannel.ToRGB()
 	b := bChannel.ToRGB()
 
-	a := AlphaMax
-	hasAlpha := false
-	if matches[rgbAValueIdx] != "" {
-		hasAlpha = true
-		a, _ = strconv.ParseFloat(matches[rgbAValueIdx], 64)
-		a = clamp(a, AlphaMin, AlphaMax)
+	a, err := parseAlpha(matches[rgbAValueIdx], matches[rgbAPercentIdx])
+	if err != nil {
+		return Color{}, false, err
 	}
+	hasAlpha := matches[rgbAValueIdx] != ""
 
 	return Color{R: r, G: g, B: b, A: a}, hasAlpha, nil
 }
@@ -285,27 +433,30 @@ func parseRGB(input string) (Color, bool, error) {
 // parseHSL parses an HSL/HSLA color string and converts to RGB
 func parseHSL(input string) (Color, bool, error) {
 	matches := hslPattern.FindStringSubmatch(input)
+	if matches == nil {
+		matches = hslModernPattern.FindStringSubmatch(input)
+	}
 	if matches == nil {
 		return Color{}, false, fmt.Errorf("invalid HSL format: %s", input)
 	}
 
-	h, _ := strconv.ParseFloat(matches[1], 64)
+	hChannel, err := NewHueChannel(matches[1])
+	if err != nil {
+		return Color{}, false, fmt.Errorf("invalid hue: %w", err)
+	}
+	h := hChannel.Value()
 	s, _ := strconv.ParseFloat(matches[2], 64)
 	l, _ := strconv.ParseFloat(matches[3], 64)
 
-	a := AlphaMax
-	hasAlpha := false
-
-	if matches[4] != "" {
-		hasAlpha = true
-		a, _ = strconv.ParseFloat(matches[4], 64)
+	a, err := parseAlpha(matches[hslAValueIdx], matches[hslAPercentIdx])
+	if err != nil {
+		return Color{}, false, err
 	}
+	hasAlpha := matches[hslAValueIdx] != ""
 
 	// Clamp values
-	h = clamp(h, 0, HueMax)
 	s = clamp(s, 0, SaturationMax)
 	l = clamp(l, 0, LightnessMax)
-	a = clamp(a, AlphaMin, AlphaMax)
 
 	// Convert HSL to RGB
 	r, g, b := hslToRGB(h, s, l)
@@ -320,23 +471,23 @@ func parseHSB(input string) (Color, bool, error) {
 		return Color{}, false, fmt.Errorf("invalid HSB format: %s", input)
 	}
 
-	h, _ := strconv.ParseFloat(matches[1], 64)
+	hChannel, err := NewHueChannel(matches[1])
+	if err != nil {
+		return Color{}, false, fmt.Errorf("invalid hue: %w", err)
+	}
+	h := hChannel.Value()
 	s, _ := strconv.ParseFloat(matches[2], 64)
 	v, _ := strconv.ParseFloat(matches[3], 64)
 
-	a := AlphaMax
-	hasAlpha := false
-
-	if matches[4] != "" {
-		hasAlpha = true
-		a, _ = strconv.ParseFloat(matches[4], 64)
+	a, err := parseAlpha(matches[4], matches[5])
+	if err != nil {
+		return Color{}, false, err
 	}
+	hasAlpha := matches[4] != ""
 
 	// Clamp values
-	h = clamp(h, 0, HueMax)
 	s = clamp(s, 0, SaturationMax)
 	v = clamp(v, 0, SaturationMax)
-	a = clamp(a, AlphaMin, AlphaMax)
 
 	// Convert HSB to RGB
 	r, g, b := hsbToRGB(h, s, v)
@@ -344,90 +495,156 @@ func parseHSB(input string) (Color, bool, error) {
 	return Color{R: r, G: g, B: b, A: a}, hasAlpha, nil
 }
 
-// parseOKLCH parses an OKLCH color string and converts to RGB
-func parseOKLCH(input string) (Color, error) {
+// parseOKLCH parses an OKLCH color string and converts to linear sRGB
+func parseOKLCH(input string) (LinearColor, error) {
 	matches := oklchPattern.FindStringSubmatch(input)
 	if matches == nil {
-		return Color{}, fmt.Errorf("invalid OKLCH format: %s", input)
+		return LinearColor{}, fmt.Errorf("invalid OKLCH format: %s", input)
 	}
 
 	// Parse lightness (can be 0-1 or 0-100%)
 	lChannel, err := NewLightnessChannel(matches[oklchLValueIdx], matches[oklchLPercentIdx] == "%")
 	if err != nil {
-		return Color{}, fmt.Errorf("invalid lightness: %w", err)
+		return LinearColor{}, fmt.Errorf("invalid lightness: %w", err)
 	}
 	l := lChannel.ToFraction()
 
-	// Parse chroma (0-0.4)
+	// Parse chroma (non-negative, unbounded above)
 	cChannel, err := NewChromaChannel(matches[oklchCValueIdx])
 	if err != nil {
-		return Color{}, fmt.Errorf("invalid chroma: %w", err)
+		return LinearColor{}, fmt.Errorf("invalid chroma: %w", err)
 	}
 	c := cChannel.Value()
 
-	// Parse hue (0-360, optional)
+	// Parse hue (any angle, wrapped to 0-360, optional)
 	h := 0.0
 	if matches[oklchHValueIdx] != "" {
 		hChannel, err := NewHueChannel(matches[oklchHValueIdx])
 		if err != nil {
-			return Color{}, fmt.Errorf("invalid hue: %w", err)
+			return LinearColor{}, fmt.Errorf("invalid hue: %w", err)
 		}
 		h = hChannel.Value()
 	}
 
 	// Parse alpha
-	a := AlphaMax
-	if matches[oklchAValueIdx] != "" {
-		a, _ = strconv.ParseFloat(matches[oklchAValueIdx], 64)
-		a = clamp(a, AlphaMin, AlphaMax)
+	a, err := parseAlpha(matches[oklchAValueIdx], matches[oklchAPercentIdx])
+	if err != nil {
+		return LinearColor{}, err
 	}
 
-	r, g, b := oklchToRGB(l, c, h)
-	return Color{R: r, G: g, B: b, A: a}, nil
+	r, g, b := oklchToLinear(l, c, h)
+	return LinearColor{R: r, G: g, B: b, A: a}, nil
+}
+
+// parseOKLab parses an OKLab color string and converts to linear sRGB
+func parseOKLab(input string) (LinearColor, error) {
+	matches := oklabPattern.FindStringSubmatch(input)
+	if matches == nil {
+		return LinearColor{}, fmt.Errorf("invalid OKLab format: %s", input)
+	}
+
+	// Parse lightness (can be 0-1 or 0-100%)
+	lChannel, err := NewLightnessChannel(matches[oklabLValueIdx], matches[oklabLPercentIdx] == "%")
+	if err != nil {
+		return LinearColor{}, fmt.Errorf("invalid lightness: %w", err)
+	}
+	l := lChannel.ToFraction()
+
+	// Parse a and b (signed, 100% = 0.4)
+	a, _ := strconv.ParseFloat(matches[oklabAValueIdx], 64)
+	if matches[oklabAPercentIdx] == "%" {
+		a = a / 100 * OKLAB_AB_Max
+	}
+	bVal, _ := strconv.ParseFloat(matches[oklabBValueIdx], 64)
+	if matches[oklabBPercentIdx] == "%" {
+		bVal = bVal / 100 * OKLAB_AB_Max
+	}
+
+	// Parse alpha
+	alpha, err := parseAlpha(matches[oklabAlphaIdx], matches[oklabAlphaPctIdx])
+	if err != nil {
+		return LinearColor{}, err
+	}
+
+	r, g, b := oklabToLinear(l, a, bVal)
+	return LinearColor{R: r, G: g, B: b, A: alpha}, nil
 }
 
-// parseLAB parses a LAB color string and converts to RGB
-func parseLAB(input string) (Color, error) {
+// parseLAB parses a LAB color string and converts to linear sRGB
+func parseLAB(input string) (LinearColor, error) {
 	matches := labPattern.FindStringSubmatch(input)
 	if matches == nil {
-		return Color{}, fmt.Errorf("invalid LAB format: %s", input)
+		return LinearColor{}, fmt.Errorf("invalid LAB format: %s", input)
 	}
 
 	l, _ := strconv.ParseFloat(matches[1], 64)
 	a, _ := strconv.ParseFloat(matches[2], 64)
 	bVal, _ := strconv.ParseFloat(matches[3], 64)
 
-	alpha := AlphaMax
-	if matches[4] != "" {
-		alpha, _ = strconv.ParseFloat(matches[4], 64)
+	alpha, err := parseAlpha(matches[4], matches[5])
+	if err != nil {
+		return LinearColor{}, err
 	}
 
 	// Convert LAB to RGB via XYZ
-	r, g, bVal := labToRGB(l, a, bVal)
+	r, g, bVal := labToLinear(l, a, bVal)
+
+	return LinearColor{R: r, G: g, B: bVal, A: alpha}, nil
+}
+
+// parseLCH parses a CIE LCH color string and converts to linear sRGB
+func parseLCH(input string) (LinearColor, error) {
+	matches := lchPattern.FindStringSubmatch(input)
+	if matches == nil {
+		return LinearColor{}, fmt.Errorf("invalid LCH format: %s", input)
+	}
+
+	// Parse lightness (0-100, 100% = 100)
+	l, _ := strconv.ParseFloat(matches[lchLValueIdx], 64)
+	l = clamp(l, 0, LAB_L_Max)
+
+	// Parse chroma (0-150, 100% = 150)
+	c, _ := strconv.ParseFloat(matches[lchCValueIdx], 64)
+	if matches[lchCPercentIdx] == "%" {
+		c = c / 100 * LCH_C_Max
+	}
+
+	hChannel, err := NewHueChannel(matches[lchHValueIdx])
+	if err != nil {
+		return LinearColor{}, fmt.Errorf("invalid hue: %w", err)
+	}
+	h := hChannel.Value()
+
+	// Parse alpha
+	a, err := parseAlpha(matches[lchAValueIdx], matches[lchAPercentIdx])
+	if err != nil {
+		return LinearColor{}, err
+	}
 
-	return Color{R: r, G: g, B: bVal, A: alpha}, nil
+	r, g, b := lchToLinear(l, c, h)
+	return LinearColor{R: r, G: g, B: b, A: a}, nil
 }
 
-// parseXYZ parses an XYZ color string and converts to RGB
-func parseXYZ(input string) (Color, error) {
+// parseXYZ parses an XYZ color string and converts to linear sRGB
+func parseXYZ(input string) (LinearColor, error) {
 	matches := xyzPattern.FindStringSubmatch(input)
 	if matches == nil {
-		return Color{}, fmt.Errorf("invalid XYZ format: %s", input)
+		return LinearColor{}, fmt.Errorf("invalid XYZ format: %s", input)
 	}
 
 	x, _ := strconv.ParseFloat(matches[1], 64)
 	y, _ := strconv.ParseFloat(matches[2], 64)
 	z, _ := strconv.ParseFloat(matches[3], 64)
 
-	alpha := AlphaMax
-	if matches[4] != "" {
-		alpha, _ = strconv.ParseFloat(matches[4], 64)
+	alpha, err := parseAlpha(matches[4], matches[5])
+	if err != nil {
+		return LinearColor{}, err
 	}
 
 	// Convert XYZ to RGB
-	r, g, b := xyzToRGB(x, y, z)
+	r, g, b := xyzToLinear(x, y, z)
 
-	return Color{R: r, G: g, B: b, A: alpha}, nil
+	return LinearColor{R: r, G: g, B: b, A: alpha}, nil
 }
 
 // parseHWB parses an HWB color string and converts to RGB
@@ -437,23 +654,23 @@ func parseHWB(input string) (Color, bool, error) {
 		return Color{}, false, fmt.Errorf("invalid HWB format: %s", input)
 	}
 
-	h, _ := strconv.ParseFloat(matches[1], 64)
+	hChannel, err := NewHueChannel(matches[1])
+	if err != nil {
+		return Color{}, false, fmt.Errorf("invalid hue: %w", err)
+	}
+	h := hChannel.Value()
 	w, _ := strconv.ParseFloat(matches[2], 64)
 	bVal, _ := strconv.ParseFloat(matches[3], 64)
 
-	a := AlphaMax
-	hasAlpha := false
-
-	if matches[4] != "" {
-		hasAlpha = true
-		a, _ = strconv.ParseFloat(matches[4], 64)
+	a, err := parseAlpha(matches[4], matches[5])
+	if err != nil {
+		return Color{}, false, err
 	}
+	hasAlpha := matches[4] != ""
 
 	// Clamp values
-	h = clamp(h, 0, HueMax)
 	w = clamp(w, 0, LightnessMax)
 	bVal = clamp(bVal, 0, LightnessMax)
-	a = clamp(a, AlphaMin, AlphaMax)
 
 	// Convert HWB to RGB
 	r, g, b := hwbToRGB(h, w, bVal)
@@ -461,6 +678,32 @@ func parseHWB(input string) (Color, bool, error) {
 	return Color{R: r, G: g, B: b, A: a}, hasAlpha, nil
 }
 
+// parseHCT parses a Material HCT color string and converts to linear sRGB
+func parseHCT(input string) (LinearColor, error) {
+	matches := hctPattern.FindStringSubmatch(input)
+	if matches == nil {
+		return LinearColor{}, fmt.Errorf("invalid HCT format: %s", input)
+	}
+
+	hChannel, err := NewHueChannel(matches[1])
+	if err != nil {
+		return LinearColor{}, fmt.Errorf("invalid hue: %w", err)
+	}
+	h := hChannel.Value()
+	c, _ := strconv.ParseFloat(matches[2], 64)
+	t, _ := strconv.ParseFloat(matches[3], 64)
+	t = clamp(t, 0, HCT_T_Max)
+
+	a, err := parseAlpha(matches[4], matches[5])
+	if err != nil {
+		return LinearColor{}, err
+	}
+
+	// Chroma beyond the sRGB gamut is reduced at the same hue and tone
+	r, g, b := hctToLinear(h, c, t)
+	return LinearColor{R: r, G: g, B: b, A: a}, nil
+}
+
 // parseCMYK parses a CMYK color string and converts to RGB
 func parseCMYK(input string) (Color, error) {
 	matches := cmykPattern.FindStringSubmatch(input)
@@ -473,9 +716,9 @@ func parseCMYK(input string) (Color, error) {
 	y, _ := strconv.ParseFloat(matches[3], 64)
 	k, _ := strconv.ParseFloat(matches[4], 64)
 
-	a := AlphaMax
-	if matches[5] != "" {
-		a, _ = strconv.ParseFloat(matches[5], 64)
+	a, err := parseAlpha(matches[5], matches[6])
+	if err != nil {
+		return Color{}, err
 	}
 
 	// Clamp values
@@ -483,7 +726,6 @@ func parseCMYK(input string) (Color, error) {
 	m = clamp(m, 0, SaturationMax)
 	y = clamp(y, 0, SaturationMax)
 	k = clamp(k, 0, SaturationMax)
-	a = clamp(a, AlphaMin, AlphaMax)
 
 	// Convert CMYK to RGB
 	r, g, b := cmykToRGB(c, m, y, k)
@@ -491,6 +733,54 @@ func parseCMYK(input string) (Color, error) {
 	return Color{R: r, G: g, B: b, A: a}, nil
 }
 
+// parseColorFunction parses a CSS color() string and converts to linear sRGB
+// Returns the predefined color space as the format
+func parseColorFunction(input string) (LinearColor, ColorFormat, error) {
+	matches := colorPattern.FindStringSubmatch(input)
+	if matches == nil {
+		return LinearColor{}, "", fmt.Errorf("invalid color() format: %s", input)
+	}
+
+	format := ColorFormat(strings.ToLower(matches[colorSpaceIdx]))
+	if format == FormatXYZ {
+		format = FormatXYZD65 // color(xyz ...) is an alias for xyz-d65
+	}
+	space := predefinedSpaces[format]
+
+	// Channels may be out of range (wide-gamut input); 100% maps to 1.0
+	channel := func(valueIdx, percentIdx int) float64 {
+		v, _ := strconv.ParseFloat(matches[valueIdx], 64)
+		if matches[percentIdx] == "%" {
+			v /= 100
+		}
+		return v
+	}
+	c1 := channel(colorC1ValueIdx, colorC1PercentIdx)
+	c2 := channel(colorC2ValueIdx, colorC2PercentIdx)
+	c3 := channel(colorC3ValueIdx, colorC3PercentIdx)
+
+	a, err := parseAlpha(matches[colorAValueIdx], matches[colorAPercentIdx])
+	if err != nil {
+		return LinearColor{}, "", err
+	}
+
+	r, g, b := predefinedToLinear(space, c1, c2, c3)
+	return LinearColor{R: r, G: g, B: b, A: a}, format, nil
+}
+
+// parseAlpha parses the alpha captured by alphaToken or legacyAlphaToken,
+// as a number or percentage clamped to 0-1; an empty value is opaque
+func parseAlpha(value, percent string) (float64, error) {
+	if value == "" {
+		return AlphaMax, nil
+	}
+	aChannel, err := NewAlphaChannel(value, percent == "%")
+	if err != nil {
+		return 0, fmt.Errorf("invalid alpha value: %w", err)
+	}
+	return aChannel.ToFraction(), nil
+}
+
 // clamp clamps a value between min and max
 func clamp(v, min, max float64) float64 {
 	if v < min {
@@ -2,7 +2,9 @@ package internal
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 )
 
 // ChannelValue represents a numeric value that can be absolute or percentage
@@ -70,7 +72,25 @@ func (lc LightnessChannel) ToFraction() float64 {
 	return clamp(lc.AsFraction(), 0, OKLCH_L_Max)
 }
 
-// ChromaChannel represents OKLCH chroma (0-0.4)
+// AlphaChannel represents opacity (0-1 or 0-100%)
+type AlphaChannel struct {
+	ChannelValue
+}
+
+func NewAlphaChannel(value string, hasPercent bool) (AlphaChannel, error) {
+	cv, err := NewChannelValue(value, hasPercent)
+	if err != nil {
+		return AlphaChannel{}, err
+	}
+	return AlphaChannel{ChannelValue: cv}, nil
+}
+
+func (ac AlphaChannel) ToFraction() float64 {
+	return clamp(ac.AsFraction(), AlphaMin, AlphaMax)
+}
+
+// ChromaChannel represents OKLCH chroma (non-negative; 0.4 is the usual
+// display range, but wide-gamut colors may exceed it)
 type ChromaChannel struct {
 	value float64
 }
@@ -80,22 +100,51 @@ func NewChromaChannel(value string) (ChromaChannel, error) {
 	if err != nil {
 		return ChromaChannel{}, err
 	}
-	return ChromaChannel{value: clamp(v, 0, OKLCH_C_Max)}, nil
+	return ChromaChannel{value: math.Max(v, 0)}, nil
 }
 
 func (cc ChromaChannel) Value() float64 { return cc.value }
 
-// HueChannel represents hue angle (0-360)
+// HueChannel represents hue angle, normalized to 0-360
 type HueChannel struct {
 	value float64
 }
 
+// NewHueChannel parses a CSS <hue>: a bare number of degrees or an angle with a
+// deg, grad, rad or turn unit. The result wraps around the color wheel as in CSS,
+// so -30 becomes 330 and 400 becomes 40.
 func NewHueChannel(value string) (HueChannel, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(value, "deg"):
+		value = strings.TrimSuffix(value, "deg")
+	case strings.HasSuffix(value, "grad"):
+		value = strings.TrimSuffix(value, "grad")
+		unit = FullCircle / 400
+	case strings.HasSuffix(value, "rad"):
+		value = strings.TrimSuffix(value, "rad")
+		unit = 180 / math.Pi
+	case strings.HasSuffix(value, "turn"):
+		value = strings.TrimSuffix(value, "turn")
+		unit = FullCircle
+	}
+
 	v, err := strconv.ParseFloat(value, 64)
 	if err != nil {
 		return HueChannel{}, err
 	}
-	return HueChannel{value: clamp(v, 0, HueMax)}, nil
+	return HueChannel{value: normalizeHue(v * unit)}, nil
 }
 
 func (hc HueChannel) Value() float64 { return hc.value }
+
+// normalizeHue wraps an angle in degrees into the range [0, 360)
+func normalizeHue(h float64) float64 {
+	h = math.Mod(h, FullCircle)
+	if h < 0 {
+		h += FullCircle
+	}
+	return h
+}
@@ -1,6 +1,9 @@
 package internal
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestChannelValue_AsFraction(t *testing.T) {
 	tests := []struct {
@@ -84,7 +87,8 @@ func TestChromaChannel_Value(t *testing.T) {
 	}{
 		{"zero", "0", 0.0},
 		{"max chroma", "0.4", 0.4},
-		{"clamped above max", "1.0", 0.4},
+		{"above display range", "0.5", 0.5},
+		{"clamped below zero", "-0.1", 0.0},
 		{"small chroma", "0.1", 0.1},
 	}
 	for _, tt := range tests {
@@ -110,8 +114,14 @@ func TestHueChannel_Value(t *testing.T) {
 		{"90 degrees", "90", 90.0},
 		{"180 degrees", "180", 180.0},
 		{"270 degrees", "270", 270.0},
-		{"360 degrees", "360", 360.0},
-		{"clamped above 360", "400", 360.0},
+		{"360 wraps to 0", "360", 0.0},
+		{"wrapped above 360", "400", 40.0},
+		{"wrapped negative", "-30", 330.0},
+		{"deg unit", "120deg", 120.0},
+		{"turn unit", "0.5turn", 180.0},
+		{"grad unit", "100grad", 90.0},
+		{"rad unit", "3.141592653589793rad", 180.0},
+		{"uppercase unit", "1TURN", 0.0},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -119,7 +129,7 @@ func TestHueChannel_Value(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			if hc.Value() != tt.expected {
+			if math.Abs(hc.Value()-tt.expected) > 1e-9 {
 				t.Errorf("expected %f, got %f", tt.expected, hc.Value())
 			}
 		})
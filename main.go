@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/InkyQuill/color-mcp/internal"
@@ -48,10 +49,12 @@ type InputSchema struct {
 }
 
 type Property struct {
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	Enum        []string  `json:"enum,omitempty"`
-	Items       *Property `json:"items,omitempty"`
+	Type        string              `json:"type"`
+	Description string              `json:"description"`
+	Enum        []string            `json:"enum,omitempty"`
+	Items       *Property           `json:"items,omitempty"`
+	Properties  map[string]Property `json:"properties,omitempty"`
+	Required    []string            `json:"required,omitempty"`
 }
 
 type ToolCallParams struct {
@@ -145,7 +148,7 @@ func handleToolsList(req *MCPRequest) {
 	tools := []Tool{
 		{
 			Name:        "convert_color",
-			Description: "Convert colors between different web color formats (HEX, RGB, HSL, OKLCH, LAB, XYZ, HWB, CMYK, etc.)",
+			Description: "Convert colors between different web color formats (HEX, RGB, HSL, OKLCH, LAB, HCT, XYZ, HWB, CMYK, etc.)",
 			InputSchema: InputSchema{
 				Type: "object",
 				Properties: map[string]Property{
@@ -162,6 +165,29 @@ func handleToolsList(req *MCPRequest) {
 						Type:        "boolean",
 						Description: "Whether to preserve the alpha channel (default: true)",
 					},
+					"modern_syntax": {
+						Type:        "boolean",
+						Description: "Emit CSS Color 4 space-separated syntax for rgb/hsl, e.g. 'rgb(255 0 0 / 0.50)' (default: false)",
+					},
+					"named_fallback": {
+						Type:        "boolean",
+						Description: "For target_format 'named', return the nearest CSS keyword by OKLCH ΔE when there is no exact match (default: false)",
+					},
+					"gamut_mapping": {
+						Type:        "string",
+						Description: "How colors outside sRGB are mapped for sRGB-based targets (hex, rgb, hsl, ...): 'clip' clamps each channel, 'css4' uses the CSS Color 4 algorithm, 'chroma-reduce' lowers OKLCH chroma only (default: clip)",
+						Enum:        internal.GetGamutMappings(),
+					},
+					"white_point": {
+						Type:        "string",
+						Description: "Reference white for lab/lch output; CSS lab() and lch() use D50 (default: D50)",
+						Enum:        internal.GetIlluminants(),
+					},
+					"chromatic_adaptation": {
+						Type:        "string",
+						Description: "Chromatic adaptation transform from the sRGB D65 white to white_point (default: bradford)",
+						Enum:        internal.GetChromaticAdaptations(),
+					},
 				},
 				Required: []string{"color", "target_format"},
 			},
@@ -207,6 +233,16 @@ func handleToolsList(req *MCPRequest) {
 						Type:        "boolean",
 						Description: "Whether to include detailed component breakdown (default: false)",
 					},
+					"contrast_algorithm": {
+						Type:        "string",
+						Description: "Contrast measure: wcag2 ratio, apca Lc with color1 as text on color2, or both (default: wcag2)",
+						Enum:        internal.GetContrastAlgorithms(),
+					},
+					"metric": {
+						Type:        "string",
+						Description: "Color difference formula, with color1 as the reference for deltaE94 and cmc; the verdict uses thresholds calibrated for it (default: deltaEOK)",
+						Enum:        internal.GetDeltaEMetrics(),
+					},
 				},
 				Required: []string{"color1", "color2"},
 			},
@@ -233,247 +269,1311 @@ func handleToolsList(req *MCPRequest) {
 						Type:        "boolean",
 						Description: "Whether to preserve the alpha channel (default: true)",
 					},
+					"modern_syntax": {
+						Type:        "boolean",
+						Description: "Emit CSS Color 4 space-separated syntax for rgb/hsl, e.g. 'rgb(255 0 0 / 0.50)' (default: false)",
+					},
+					"named_fallback": {
+						Type:        "boolean",
+						Description: "For target_format 'named', return the nearest CSS keyword by OKLCH ΔE when there is no exact match (default: false)",
+					},
+					"gamut_mapping": {
+						Type:        "string",
+						Description: "How colors outside sRGB are mapped for sRGB-based targets (hex, rgb, hsl, ...): 'clip' clamps each channel, 'css4' uses the CSS Color 4 algorithm, 'chroma-reduce' lowers OKLCH chroma only (default: clip)",
+						Enum:        internal.GetGamutMappings(),
+					},
+					"white_point": {
+						Type:        "string",
+						Description: "Reference white for lab/lch output; CSS lab() and lch() use D50 (default: D50)",
+						Enum:        internal.GetIlluminants(),
+					},
+					"chromatic_adaptation": {
+						Type:        "string",
+						Description: "Chromatic adaptation transform from the sRGB D65 white to white_point (default: bradford)",
+						Enum:        internal.GetChromaticAdaptations(),
+					},
 				},
 				Required: []string{"colors", "target_format"},
 			},
 		},
-	}
-
-	response := MCPResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: map[string]interface{}{
-			"tools": tools,
+		{
+			Name:        "check_gamut",
+			Description: "Check whether a color fits inside the sRGB, Display P3, Rec.2020 and ProPhoto RGB gamuts, with the distance outside and the nearest in-gamut color",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"color": {
+						Type:        "string",
+						Description: "Color value in any supported format (e.g., 'oklch(0.7 0.3 150)', 'color(display-p3 0 1 0)')",
+					},
+					"gamut_mapping": {
+						Type:        "string",
+						Description: "Method used to find the nearest in-gamut color (default: css4)",
+						Enum:        internal.GetGamutMappings(),
+					},
+				},
+				Required: []string{"color"},
+			},
 		},
-	}
-	sendResponse(response)
+		{
+			Name:        "mix_colors",
+			Description: "Interpolate between two or more colors at given positions in a chosen color space",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"colors": {
+						Type:        "array",
+						Description: "Two or more colors in any supported format, used as evenly spaced stops",
+						Items: &Property{
+							Type: "string",
+						},
+					},
+					"positions": {
+						Type:        "array",
+						Description: "Positions to sample between 0 (first color) and 1 (last color) (default: [0.5])",
+						Items: &Property{
+							Type: "number",
+						},
+					},
+					"space": {
+						Type:        "string",
+						Description: "Color space to interpolate in (default: oklab)",
+						Enum:        internal.GetInterpolationSpaces(),
+					},
+					"hue_interpolation": {
+						Type:        "string",
+						Description: "How hues are interpolated in polar spaces (hsl, hwb, lch, oklch) (default: shorter)",
+						Enum:        internal.GetHueInterpolations(),
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+					"preserve_alpha": {
+						Type:        "boolean",
+						Description: "Whether to preserve the alpha channel (default: true)",
+					},
+				},
+				Required: []string{"colors"},
+			},
+		},
+		{
+			Name:        "generate_gradient",
+			Description: "Generate evenly or eased-spaced gradient colors in a perceptual space, with ready-to-paste linear-gradient()/radial-gradient() CSS",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"colors": {
+						Type:        "array",
+						Description: "Two or more color stops in any supported format, evenly spaced along the gradient",
+						Items: &Property{
+							Type: "string",
+						},
+					},
+					"steps": {
+						Type:        "number",
+						Description: "Number of colors to generate, including both ends (default: 5)",
+					},
+					"space": {
+						Type:        "string",
+						Description: "Color space to interpolate in (default: oklch)",
+						Enum:        internal.GetInterpolationSpaces(),
+					},
+					"hue_interpolation": {
+						Type:        "string",
+						Description: "How hues are interpolated in polar spaces (default: shorter)",
+						Enum:        internal.GetHueInterpolations(),
+					},
+					"easing": {
+						Type:        "string",
+						Description: "How positions are distributed along the gradient (default: linear)",
+						Enum:        internal.GetEasings(),
+					},
+					"gradient_type": {
+						Type:        "string",
+						Description: "CSS gradient function to emit (default: linear)",
+						Enum:        internal.GetGradientTypes(),
+					},
+					"direction": {
+						Type:        "string",
+						Description: "Direction of a linear gradient (e.g. 'to bottom', '45deg') or shape/position of a radial gradient (e.g. 'ellipse at top') (default: 'to right' / 'circle')",
+					},
+				},
+				Required: []string{"colors"},
+			},
+		},
+		{
+			Name:        "adjust_color",
+			Description: "Apply a list of operations (lighten, darken, saturate, rotate hue, set a channel, ...) to a color in OKLCH, HSL, LCH or HWB",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"color": {
+						Type:        "string",
+						Description: "Color value in any supported format",
+					},
+					"operations": {
+						Type:        "array",
+						Description: "Operations applied in order",
+						Items: &Property{
+							Type:        "object",
+							Description: "A channel change, e.g. {\"channel\": \"lightness\", \"value\": 0.1} or {\"channel\": \"hue\", \"mode\": \"absolute\", \"value\": 200, \"model\": \"hsl\"}",
+							Properties: map[string]Property{
+								"channel": {
+									Type:        "string",
+									Description: "Channel to change; chroma is saturation in HSL, whiteness and blackness need HWB",
+									Enum:        internal.GetAdjustChannels(),
+								},
+								"mode": {
+									Type:        "string",
+									Description: "'relative' adds the value (negative to decrease), 'absolute' sets it (default: relative)",
+									Enum:        internal.GetAdjustModes(),
+								},
+								"value": {
+									Type:        "number",
+									Description: "Amount in the model's units: OKLCH lightness 0-1 and chroma 0-0.4, LCH lightness 0-100 and chroma 0-150, HSL/HWB percentages 0-100, hue in degrees, alpha 0-1",
+								},
+								"model": {
+									Type:        "string",
+									Description: "Color model the operation runs in (default: oklch, or hwb for whiteness/blackness)",
+									Enum:        internal.GetAdjustModels(),
+								},
+							},
+							Required: []string{"channel", "value"},
+						},
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+					"gamut_mapping": {
+						Type:        "string",
+						Description: "How results outside sRGB are mapped for sRGB-based targets (default: clip)",
+						Enum:        internal.GetGamutMappings(),
+					},
+				},
+				Required: []string{"color", "operations"},
+			},
+		},
+		{
+			Name:        "generate_harmony",
+			Description: "Build a color harmony scheme from a base color, rotating hue in OKLCH so lightness stays even, with each color's contrast against white and black",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"color": {
+						Type:        "string",
+						Description: "Base color in any supported format",
+					},
+					"harmony": {
+						Type:        "string",
+						Description: "Harmony scheme",
+						Enum:        internal.GetHarmonyTypes(),
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{"color", "harmony"},
+			},
+		},
+		{
+			Name:        "generate_tonal_scale",
+			Description: "Generate a Tailwind-style 50-950 shade scale from a base color, with lightness spaced evenly in OKLCH and the ΔE between neighboring steps",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"color": {
+						Type:        "string",
+						Description: "Base color in any supported format",
+					},
+					"steps": {
+						Type:        "number",
+						Description: "Number of shades, 3-21 (default: 11, labeled 50-950)",
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{"color"},
+			},
+		},
+		{
+			Name:        "material_scheme",
+			Description: "Build Material Design 3 tonal palettes (HCT tones 0-100) and the light and dark scheme color roles from a seed color, as hex",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"color": {
+						Type:        "string",
+						Description: "Seed color in any supported format",
+					},
+				},
+				Required: []string{"color"},
+			},
+		},
+		{
+			Name:        "fix_contrast",
+			Description: "Find the closest foreground (or background) color that reaches a WCAG contrast target, searching OKLCH lightness while keeping hue and as much chroma as possible",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"foreground": {
+						Type:        "string",
+						Description: "Text color in any supported format",
+					},
+					"background": {
+						Type:        "string",
+						Description: "Background color in any supported format",
+					},
+					"target": {
+						Type:        "string",
+						Description: "Contrast target: AA (4.5:1), AAA (7:1), AA-large (3:1) or a ratio such as 5.5 (default: AA)",
+					},
+					"adjust": {
+						Type:        "string",
+						Description: "Which color to change (default: foreground)",
+						Enum:        internal.GetContrastSides(),
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{"foreground", "background"},
+			},
+		},
+		{
+			Name:        "simulate_cvd",
+			Description: "Simulate how colors look with protan, deutan or tritan color vision deficiency (Machado 2009, in linear RGB) or achromatopsia, at a severity from anomalous trichromacy to dichromacy",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"colors": {
+						Type:        "array",
+						Description: "Colors in any supported format",
+						Items: &Property{
+							Type: "string",
+						},
+					},
+					"deficiency": {
+						Type:        "string",
+						Description: "Deficiency to simulate (default: all)",
+						Enum:        internal.GetCVDTypes(),
+					},
+					"severity": {
+						Type:        "number",
+						Description: "Severity from 0 (normal vision) to 1 (dichromacy); values below 1 simulate the anomalous variants (default: 1)",
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{"colors"},
+			},
+		},
+		{
+			Name:        "check_palette_cvd",
+			Description: "Audit a palette (e.g. chart series colors) for color vision deficiencies: simulate it with each deficiency, report the minimum pairwise OKLCH ΔE and flag pairs that become indistinguishable, with a pass/fail result",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"colors": {
+						Type:        "array",
+						Description: "Two or more palette colors in any supported format",
+						Items: &Property{
+							Type: "string",
+						},
+					},
+					"deficiencies": {
+						Type:        "array",
+						Description: "Deficiencies to check (default: protan, deutan, tritan)",
+						Items: &Property{
+							Type: "string",
+							Enum: internal.GetCVDTypes(),
+						},
+					},
+					"severity": {
+						Type:        "number",
+						Description: "Severity from 0 (normal vision) to 1 (dichromacy) (default: 1)",
+					},
+				},
+				Required: []string{"colors"},
+			},
+		},
+		{
+			Name:        "find_nearest_color",
+			Description: "Find the closest colors to a query color in a palette (a list of colors or brand tokens, or a built-in set: CSS names, Tailwind, Material, web-safe), with their ΔE under a chosen metric",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"color": {
+						Type:        "string",
+						Description: "Query color in any supported format",
+					},
+					"palette": {
+						Type:        "array",
+						Description: "Palette colors in any supported format, optionally named as 'name: color' (e.g. 'brand-primary: #3b82f6')",
+						Items: &Property{
+							Type: "string",
+						},
+					},
+					"builtin": {
+						Type:        "string",
+						Description: "Built-in palette to search instead of palette",
+						Enum:        internal.GetBuiltinPalettes(),
+					},
+					"count": {
+						Type:        "number",
+						Description: "Number of matches to return, 1-50 (default: 5)",
+					},
+					"metric": {
+						Type:        "string",
+						Description: "Color difference formula (default: deltaEOK)",
+						Enum:        internal.GetDeltaEMetrics(),
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{"color"},
+			},
+		},
+		{
+			Name:        "cluster_colors",
+			Description: "Group a list of colors by perceptual distance (OKLCH ΔE threshold, agglomerative or k-means clustering in OKLab) and return each cluster's medoid and members, to consolidate near-duplicate tokens",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"colors": {
+						Type:        "array",
+						Description: "Colors in any supported format (up to 500)",
+						Items: &Property{
+							Type: "string",
+						},
+					},
+					"method": {
+						Type:        "string",
+						Description: "threshold keeps every cluster within a ΔE diameter; agglomerative and kmeans build count clusters (default: threshold)",
+						Enum:        internal.GetClusterMethods(),
+					},
+					"threshold": {
+						Type:        "number",
+						Description: "For threshold: maximum OKLCH ΔE between any two members of a cluster; 0 merges exact duplicates only (default: 0.02)",
+					},
+					"count": {
+						Type:        "number",
+						Description: "For agglomerative and kmeans: number of clusters",
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format for the medoids (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{"colors"},
+			},
+		},
+		{
+			Name:        "extract_palette",
+			Description: "Extract the dominant colors of a PNG, JPEG or GIF image (local path or base64 data) with k-means or median cut in OKLab, with each color's share of the pixels",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"path": {
+						Type:        "string",
+						Description: "Path to a local PNG, JPEG or GIF file",
+					},
+					"data": {
+						Type:        "string",
+						Description: "Base64-encoded image, or a data: URL, instead of path",
+					},
+					"count": {
+						Type:        "number",
+						Description: "Number of colors to extract, 1-32 (default: 5)",
+					},
+					"method": {
+						Type:        "string",
+						Description: "Quantization method (default: kmeans)",
+						Enum:        internal.GetExtractMethods(),
+					},
+					"target_format": {
+						Type:        "string",
+						Description: "Output color format (default: hex)",
+						Enum:        internal.GetSupportedFormats(),
+					},
+				},
+				Required: []string{},
+			},
+		},
+	}
+
+	response := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"tools": tools,
+		},
+	}
+	sendResponse(response)
+}
+
+func handleToolsCall(req *MCPRequest) {
+	var params ToolCallParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		sendError(req.ID, -32602, "Invalid params", err)
+		return
+	}
+
+	var result CallToolResult
+	var err error
+
+	switch params.Name {
+	case "convert_color":
+		result, err = convertColor(params.Arguments)
+	case "detect_format":
+		result, err = detectFormat(params.Arguments)
+	case "list_formats":
+		result, err = listFormats(params.Arguments)
+	case "compare_colors":
+		result, err = compareColors(params.Arguments)
+	case "convert_colors_batch":
+		result, err = convertColorsBatch(params.Arguments)
+	case "check_gamut":
+		result, err = checkGamut(params.Arguments)
+	case "mix_colors":
+		result, err = mixColors(params.Arguments)
+	case "generate_gradient":
+		result, err = generateGradient(params.Arguments)
+	case "adjust_color":
+		result, err = adjustColor(params.Arguments)
+	case "generate_harmony":
+		result, err = generateHarmony(params.Arguments)
+	case "generate_tonal_scale":
+		result, err = generateTonalScale(params.Arguments)
+	case "material_scheme":
+		result, err = materialScheme(params.Arguments)
+	case "fix_contrast":
+		result, err = fixContrast(params.Arguments)
+	case "simulate_cvd":
+		result, err = simulateCVD(params.Arguments)
+	case "check_palette_cvd":
+		result, err = checkPaletteCVD(params.Arguments)
+	case "find_nearest_color":
+		result, err = findNearestColor(params.Arguments)
+	case "cluster_colors":
+		result, err = clusterColors(params.Arguments)
+	case "extract_palette":
+		result, err = extractPalette(params.Arguments)
+	default:
+		sendError(req.ID, -32601, "Unknown tool: "+params.Name, nil)
+		return
+	}
+
+	if err != nil {
+		result = CallToolResult{
+			Content: []ContentItem{
+				{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
+			},
+			IsError: true,
+		}
+	}
+
+	response := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+	sendResponse(response)
+}
+
+func convertColor(args map[string]interface{}) (CallToolResult, error) {
+	color, ok := args["color"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
+	}
+
+	targetFormat, ok := args["target_format"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("target_format parameter is required and must be a string")
+	}
+
+	preserveAlpha := true
+	if pa, ok := args["preserve_alpha"].(bool); ok {
+		preserveAlpha = pa
+	}
+
+	modernSyntax := false
+	if ms, ok := args["modern_syntax"].(bool); ok {
+		modernSyntax = ms
+	}
+
+	namedFallback := false
+	if nf, ok := args["named_fallback"].(bool); ok {
+		namedFallback = nf
+	}
+
+	gamutMappingArg, _ := args["gamut_mapping"].(string)
+	gamutMapping, err := internal.ParseGamutMapping(gamutMappingArg)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	whitePointArg, _ := args["white_point"].(string)
+	whitePoint, err := internal.ParseIlluminant(whitePointArg)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	adaptationArg, _ := args["chromatic_adaptation"].(string)
+	adaptation, err := internal.ParseChromaticAdaptation(adaptationArg)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	// Detect input format first
+	inputFormat, err := internal.DetectInputFormat(color)
+	if err != nil {
+		return CallToolResult{}, fmt.Errorf("failed to detect input format: %w", err)
+	}
+
+	// Convert
+	output, err := internal.ConvertWithOptions(color, targetFormat, internal.ConvertOptions{
+		PreserveAlpha: preserveAlpha,
+		ModernSyntax:  modernSyntax,
+		NamedFallback: namedFallback,
+		GamutMapping:  gamutMapping,
+		WhitePoint:    whitePoint,
+		Adaptation:    adaptation,
+	})
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	// Format result
+	resultText := fmt.Sprintf("Input color: %s (format: %s)\nOutput color: %s (format: %s)\nAlpha preserved: %t",
+		color, inputFormat, output, targetFormat, preserveAlpha)
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: resultText},
+		},
+	}, nil
+}
+
+func detectFormat(args map[string]interface{}) (CallToolResult, error) {
+	color, ok := args["color"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
+	}
+
+	format, err := internal.DetectInputFormat(color)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	resultText := fmt.Sprintf("Color: %s\nDetected format: %s", color, format)
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: resultText},
+		},
+	}, nil
+}
+
+func listFormats(args map[string]interface{}) (CallToolResult, error) {
+	formats := internal.GetSupportedFormats()
+	resultText := "Supported color formats:\n" + strings.Join(formats, ", ")
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: resultText},
+		},
+	}, nil
+}
+
+func compareColors(args map[string]interface{}) (CallToolResult, error) {
+	color1, ok := args["color1"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color1 parameter is required and must be a string")
+	}
+
+	color2, ok := args["color2"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color2 parameter is required and must be a string")
+	}
+
+	detailed := false
+	if d, ok := args["detailed"].(bool); ok {
+		detailed = d
+	}
+
+	algorithm, _ := args["contrast_algorithm"].(string)
+	contrastAlgorithm, err := internal.ParseContrastAlgorithm(algorithm)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	metric, _ := args["metric"].(string)
+
+	result, err := internal.CompareColors(color1, color2, internal.DeltaEMetric(metric))
+	if err != nil {
+		return CallToolResult{}, err
+	}
+	result.ContrastAlgorithm = contrastAlgorithm
+
+	var resultText string
+	if detailed {
+		resultText = internal.FormatComparisonDetailed(result)
+	} else {
+		resultText = internal.FormatComparisonBasic(result)
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: resultText},
+		},
+	}, nil
+}
+
+func convertColorsBatch(args map[string]interface{}) (CallToolResult, error) {
+	// Extract colors array
+	colorsInterface, ok := args["colors"].([]interface{})
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("colors parameter is required and must be an array")
+	}
+
+	// Validate array is not empty
+	if len(colorsInterface) == 0 {
+		return CallToolResult{}, fmt.Errorf("colors array cannot be empty")
+	}
+
+	// Convert to string slice
+	colors := make([]string, 0, len(colorsInterface))
+	for i, c := range colorsInterface {
+		colorStr, ok := c.(string)
+		if !ok {
+			return CallToolResult{}, fmt.Errorf("color at index %d is not a string", i)
+		}
+		if strings.TrimSpace(colorStr) == "" {
+			return CallToolResult{}, fmt.Errorf("color at index %d is empty", i)
+		}
+		colors = append(colors, colorStr)
+	}
+
+	// Extract target format
+	targetFormat, ok := args["target_format"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("target_format parameter is required and must be a string")
+	}
+
+	// Extract preserve alpha option
+	preserveAlpha := true
+	if pa, ok := args["preserve_alpha"].(bool); ok {
+		preserveAlpha = pa
+	}
+
+	// Extract output syntax option
+	modernSyntax := false
+	if ms, ok := args["modern_syntax"].(bool); ok {
+		modernSyntax = ms
+	}
+
+	namedFallback := false
+	if nf, ok := args["named_fallback"].(bool); ok {
+		namedFallback = nf
+	}
+
+	gamutMappingArg, _ := args["gamut_mapping"].(string)
+	gamutMapping, err := internal.ParseGamutMapping(gamutMappingArg)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	whitePointArg, _ := args["white_point"].(string)
+	whitePoint, err := internal.ParseIlluminant(whitePointArg)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	adaptationArg, _ := args["chromatic_adaptation"].(string)
+	adaptation, err := internal.ParseChromaticAdaptation(adaptationArg)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	opts := internal.ConvertOptions{
+		PreserveAlpha: preserveAlpha,
+		ModernSyntax:  modernSyntax,
+		NamedFallback: namedFallback,
+		GamutMapping:  gamutMapping,
+		WhitePoint:    whitePoint,
+		Adaptation:    adaptation,
+	}
+
+	// Perform batch conversion
+	results := make(map[string]string)
+	errors := make(map[string]string)
+
+	for _, color := range colors {
+		converted, err := internal.ConvertWithOptions(color, targetFormat, opts)
+		if err != nil {
+			errors[color] = err.Error()
+		} else {
+			results[color] = converted
+		}
+	}
+
+	// Format output
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Batch Conversion to %s\n", targetFormat))
+	builder.WriteString(fmt.Sprintf("Alpha preserved: %t\n", preserveAlpha))
+	builder.WriteString(fmt.Sprintf("Total colors: %d\n\n", len(colors)))
+
+	if len(results) > 0 {
+		builder.WriteString("Converted colors:\n")
+		for input, output := range results {
+			builder.WriteString(fmt.Sprintf("  %s → %s\n", input, output))
+		}
+	}
+
+	if len(errors) > 0 {
+		builder.WriteString("\nErrors:\n")
+		for input, errMsg := range errors {
+			builder.WriteString(fmt.Sprintf("  %s: %s\n", input, errMsg))
+		}
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: builder.String()},
+		},
+	}, nil
+}
+
+func checkGamut(args map[string]interface{}) (CallToolResult, error) {
+	color, ok := args["color"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
+	}
+
+	// An empty gamut_mapping means the schema default, css4, as when it is omitted
+	gamutMapping := string(internal.GamutMappingCSS4)
+	if gm, ok := args["gamut_mapping"].(string); ok && strings.TrimSpace(gm) != "" {
+		gamutMapping = gm
+	}
+
+	report, err := internal.CheckGamut(color, internal.GamutMapping(gamutMapping))
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatGamutReport(report)},
+		},
+	}, nil
+}
+
+func mixColors(args map[string]interface{}) (CallToolResult, error) {
+	colors, err := stringArrayArg(args, "colors")
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	positions := []float64{0.5}
+	if _, ok := args["positions"]; ok {
+		positions, err = numberArrayArg(args, "positions")
+		if err != nil {
+			return CallToolResult{}, err
+		}
+	}
+
+	space, _ := args["space"].(string)
+	hueMethod, _ := args["hue_interpolation"].(string)
+
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	preserveAlpha := true
+	if pa, ok := args["preserve_alpha"].(bool); ok {
+		preserveAlpha = pa
+	}
+
+	result, err := internal.InterpolateColors(colors, positions, internal.ColorFormat(space),
+		internal.HueInterpolation(hueMethod), targetFormat, internal.ConvertOptions{PreserveAlpha: preserveAlpha})
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatInterpolation(result)},
+		},
+	}, nil
+}
+
+func generateGradient(args map[string]interface{}) (CallToolResult, error) {
+	colors, err := stringArrayArg(args, "colors")
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	opts := internal.GradientOptions{}
+	if steps, ok := args["steps"].(float64); ok {
+		if steps != float64(int(steps)) {
+			return CallToolResult{}, fmt.Errorf("steps must be an integer")
+		}
+		opts.Steps = int(steps)
+	}
+	if space, ok := args["space"].(string); ok {
+		opts.Space = internal.ColorFormat(space)
+	}
+	if hue, ok := args["hue_interpolation"].(string); ok {
+		opts.Hue = internal.HueInterpolation(hue)
+	}
+	if easing, ok := args["easing"].(string); ok {
+		opts.Easing = internal.Easing(easing)
+	}
+	if gradientType, ok := args["gradient_type"].(string); ok {
+		opts.Type = internal.GradientType(gradientType)
+	}
+	if direction, ok := args["direction"].(string); ok {
+		opts.Direction = direction
+	}
+
+	result, err := internal.GenerateGradient(colors, opts)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatGradient(result)},
+		},
+	}, nil
 }
 
-func handleToolsCall(req *MCPRequest) {
-	var params ToolCallParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		sendError(req.ID, -32602, "Invalid params", err)
-		return
+func adjustColor(args map[string]interface{}) (CallToolResult, error) {
+	color, ok := args["color"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
 	}
 
-	var result CallToolResult
-	var err error
+	items, ok := args["operations"].([]interface{})
+	if !ok || len(items) == 0 {
+		return CallToolResult{}, fmt.Errorf("operations parameter is required and must be a non-empty array")
+	}
+	ops := make([]internal.AdjustOperation, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return CallToolResult{}, fmt.Errorf("operation at index %d is not an object", i)
+		}
+		channel, ok := obj["channel"].(string)
+		if !ok {
+			return CallToolResult{}, fmt.Errorf("operation at index %d: channel is required and must be a string", i)
+		}
+		value, ok := obj["value"].(float64)
+		if !ok {
+			return CallToolResult{}, fmt.Errorf("operation at index %d: value is required and must be a number", i)
+		}
+		mode, _ := obj["mode"].(string)
+		model, _ := obj["model"].(string)
+		ops = append(ops, internal.AdjustOperation{
+			Channel: internal.AdjustChannel(channel),
+			Mode:    internal.AdjustMode(mode),
+			Value:   value,
+			Model:   internal.ColorFormat(model),
+		})
+	}
 
-	switch params.Name {
-	case "convert_color":
-		result, err = convertColor(params.Arguments)
-	case "detect_format":
-		result, err = detectFormat(params.Arguments)
-	case "list_formats":
-		result, err = listFormats(params.Arguments)
-	case "compare_colors":
-		result, err = compareColors(params.Arguments)
-	case "convert_colors_batch":
-		result, err = convertColorsBatch(params.Arguments)
-	default:
-		sendError(req.ID, -32601, "Unknown tool: "+params.Name, nil)
-		return
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
 	}
 
+	gamutMapping, _ := args["gamut_mapping"].(string)
+
+	result, err := internal.AdjustColor(color, ops, targetFormat, internal.ConvertOptions{
+		PreserveAlpha: true,
+		GamutMapping:  internal.GamutMapping(gamutMapping),
+	})
 	if err != nil {
-		result = CallToolResult{
-			Content: []ContentItem{
-				{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
-			},
-			IsError: true,
-		}
+		return CallToolResult{}, err
 	}
 
-	response := MCPResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result:  result,
-	}
-	sendResponse(response)
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatAdjustment(result)},
+		},
+	}, nil
 }
 
-func convertColor(args map[string]interface{}) (CallToolResult, error) {
+func generateHarmony(args map[string]interface{}) (CallToolResult, error) {
 	color, ok := args["color"].(string)
 	if !ok {
 		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
 	}
 
-	targetFormat, ok := args["target_format"].(string)
+	harmony, ok := args["harmony"].(string)
 	if !ok {
-		return CallToolResult{}, fmt.Errorf("target_format parameter is required and must be a string")
-	}
-
-	preserveAlpha := true
-	if pa, ok := args["preserve_alpha"].(bool); ok {
-		preserveAlpha = pa
+		return CallToolResult{}, fmt.Errorf("harmony parameter is required and must be a string")
 	}
 
-	// Detect input format first
-	inputFormat, err := internal.DetectInputFormat(color)
-	if err != nil {
-		return CallToolResult{}, fmt.Errorf("failed to detect input format: %w", err)
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
 	}
 
-	// Convert
-	output, err := internal.Convert(color, targetFormat, preserveAlpha)
+	result, err := internal.GenerateHarmony(color, internal.HarmonyType(harmony), targetFormat, internal.ConvertOptions{PreserveAlpha: true})
 	if err != nil {
 		return CallToolResult{}, err
 	}
 
-	// Format result
-	resultText := fmt.Sprintf("Input color: %s (format: %s)\nOutput color: %s (format: %s)\nAlpha preserved: %t",
-		color, inputFormat, output, targetFormat, preserveAlpha)
-
 	return CallToolResult{
 		Content: []ContentItem{
-			{Type: "text", Text: resultText},
+			{Type: "text", Text: internal.FormatHarmony(result)},
 		},
 	}, nil
 }
 
-func detectFormat(args map[string]interface{}) (CallToolResult, error) {
+func generateTonalScale(args map[string]interface{}) (CallToolResult, error) {
 	color, ok := args["color"].(string)
 	if !ok {
 		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
 	}
 
-	format, err := internal.DetectInputFormat(color)
+	steps := 0
+	if s, ok := args["steps"].(float64); ok {
+		if s != float64(int(s)) {
+			return CallToolResult{}, fmt.Errorf("steps must be an integer")
+		}
+		steps = int(s)
+	}
+
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	result, err := internal.GenerateTonalScale(color, steps, targetFormat, internal.ConvertOptions{PreserveAlpha: true})
 	if err != nil {
 		return CallToolResult{}, err
 	}
 
-	resultText := fmt.Sprintf("Color: %s\nDetected format: %s", color, format)
-
 	return CallToolResult{
 		Content: []ContentItem{
-			{Type: "text", Text: resultText},
+			{Type: "text", Text: internal.FormatTonalScale(result)},
 		},
 	}, nil
 }
 
-func listFormats(args map[string]interface{}) (CallToolResult, error) {
-	formats := internal.GetSupportedFormats()
-	resultText := "Supported color formats:\n" + strings.Join(formats, ", ")
+func materialScheme(args map[string]interface{}) (CallToolResult, error) {
+	color, ok := args["color"].(string)
+	if !ok {
+		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
+	}
+
+	result, err := internal.GenerateMaterialScheme(color)
+	if err != nil {
+		return CallToolResult{}, err
+	}
 
 	return CallToolResult{
 		Content: []ContentItem{
-			{Type: "text", Text: resultText},
+			{Type: "text", Text: internal.FormatMaterialScheme(result)},
 		},
 	}, nil
 }
 
-func compareColors(args map[string]interface{}) (CallToolResult, error) {
-	color1, ok := args["color1"].(string)
+func fixContrast(args map[string]interface{}) (CallToolResult, error) {
+	foreground, ok := args["foreground"].(string)
 	if !ok {
-		return CallToolResult{}, fmt.Errorf("color1 parameter is required and must be a string")
+		return CallToolResult{}, fmt.Errorf("foreground parameter is required and must be a string")
 	}
 
-	color2, ok := args["color2"].(string)
+	background, ok := args["background"].(string)
 	if !ok {
-		return CallToolResult{}, fmt.Errorf("color2 parameter is required and must be a string")
+		return CallToolResult{}, fmt.Errorf("background parameter is required and must be a string")
 	}
 
-	detailed := false
-	if d, ok := args["detailed"].(bool); ok {
-		detailed = d
+	target := ""
+	switch t := args["target"].(type) {
+	case string:
+		target = t
+	case float64:
+		target = strconv.FormatFloat(t, 'f', -1, 64)
 	}
-
-	result, err := internal.CompareColors(color1, color2)
+	ratio, err := internal.ParseContrastTarget(target)
 	if err != nil {
 		return CallToolResult{}, err
 	}
 
-	var resultText string
-	if detailed {
-		resultText = internal.FormatComparisonDetailed(result)
-	} else {
-		resultText = internal.FormatComparisonBasic(result)
+	side, _ := args["adjust"].(string)
+
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	result, err := internal.FixContrast(foreground, background, ratio, internal.ContrastSide(side), targetFormat, internal.ConvertOptions{PreserveAlpha: true})
+	if err != nil {
+		return CallToolResult{}, err
 	}
 
 	return CallToolResult{
 		Content: []ContentItem{
-			{Type: "text", Text: resultText},
+			{Type: "text", Text: internal.FormatFixContrast(result)},
 		},
 	}, nil
 }
 
-func convertColorsBatch(args map[string]interface{}) (CallToolResult, error) {
-	// Extract colors array
-	colorsInterface, ok := args["colors"].([]interface{})
-	if !ok {
-		return CallToolResult{}, fmt.Errorf("colors parameter is required and must be an array")
+func simulateCVD(args map[string]interface{}) (CallToolResult, error) {
+	colors, err := stringArrayArg(args, "colors")
+	if err != nil {
+		return CallToolResult{}, err
 	}
 
-	// Validate array is not empty
-	if len(colorsInterface) == 0 {
-		return CallToolResult{}, fmt.Errorf("colors array cannot be empty")
+	deficiency, _ := args["deficiency"].(string)
+
+	severity := 1.0
+	if s, ok := args["severity"].(float64); ok {
+		severity = s
 	}
 
-	// Convert to string slice
-	colors := make([]string, 0, len(colorsInterface))
-	for i, c := range colorsInterface {
-		colorStr, ok := c.(string)
-		if !ok {
-			return CallToolResult{}, fmt.Errorf("color at index %d is not a string", i)
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	result, err := internal.SimulateCVD(colors, internal.CVDType(deficiency), severity, targetFormat, internal.ConvertOptions{PreserveAlpha: true})
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatCVDSimulation(result)},
+		},
+	}, nil
+}
+
+func checkPaletteCVD(args map[string]interface{}) (CallToolResult, error) {
+	colors, err := stringArrayArg(args, "colors")
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	var deficiencies []internal.CVDType
+	if _, ok := args["deficiencies"]; ok {
+		names, err := stringArrayArg(args, "deficiencies")
+		if err != nil {
+			return CallToolResult{}, err
 		}
-		if strings.TrimSpace(colorStr) == "" {
-			return CallToolResult{}, fmt.Errorf("color at index %d is empty", i)
+		for _, name := range names {
+			deficiencies = append(deficiencies, internal.CVDType(name))
 		}
-		colors = append(colors, colorStr)
 	}
 
-	// Extract target format
-	targetFormat, ok := args["target_format"].(string)
+	severity := 1.0
+	if s, ok := args["severity"].(float64); ok {
+		severity = s
+	}
+
+	result, err := internal.CheckPaletteCVD(colors, deficiencies, severity)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatPaletteCVD(result)},
+		},
+	}, nil
+}
+
+func findNearestColor(args map[string]interface{}) (CallToolResult, error) {
+	color, ok := args["color"].(string)
 	if !ok {
-		return CallToolResult{}, fmt.Errorf("target_format parameter is required and must be a string")
+		return CallToolResult{}, fmt.Errorf("color parameter is required and must be a string")
 	}
 
-	// Extract preserve alpha option
-	preserveAlpha := true
-	if pa, ok := args["preserve_alpha"].(bool); ok {
-		preserveAlpha = pa
+	builtin, hasBuiltin := args["builtin"].(string)
+	_, hasPalette := args["palette"]
+	if hasBuiltin && hasPalette {
+		return CallToolResult{}, fmt.Errorf("use either palette or builtin, not both")
+	}
+	if !hasBuiltin && !hasPalette {
+		return CallToolResult{}, fmt.Errorf("palette or builtin parameter is required")
 	}
 
-	// Perform batch conversion
-	results := make(map[string]string)
-	errors := make(map[string]string)
+	count := 0
+	if c, ok := args["count"].(float64); ok {
+		if c != float64(int(c)) {
+			return CallToolResult{}, fmt.Errorf("count must be an integer")
+		}
+		count = int(c)
+	}
 
-	for _, color := range colors {
-		converted, err := internal.Convert(color, targetFormat, preserveAlpha)
-		if err != nil {
-			errors[color] = err.Error()
-		} else {
-			results[color] = converted
+	metric, _ := args["metric"].(string)
+
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	opts := internal.ConvertOptions{PreserveAlpha: true}
+	var result *internal.NearestResult
+	var err error
+	if hasBuiltin {
+		result, err = internal.FindNearestBuiltinColor(color, internal.BuiltinPalette(builtin), count, internal.DeltaEMetric(metric), targetFormat, opts)
+	} else {
+		items, itemsErr := stringArrayArg(args, "palette")
+		if itemsErr != nil {
+			return CallToolResult{}, itemsErr
 		}
+		result, err = internal.FindNearestColor(color, internal.ParsePaletteEntries(items), count, internal.DeltaEMetric(metric), targetFormat, opts)
+	}
+	if err != nil {
+		return CallToolResult{}, err
 	}
 
-	// Format output
-	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("Batch Conversion to %s\n", targetFormat))
-	builder.WriteString(fmt.Sprintf("Alpha preserved: %t\n", preserveAlpha))
-	builder.WriteString(fmt.Sprintf("Total colors: %d\n\n", len(colors)))
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatNearestColor(result)},
+		},
+	}, nil
+}
 
-	if len(results) > 0 {
-		builder.WriteString("Converted colors:\n")
-		for input, output := range results {
-			builder.WriteString(fmt.Sprintf("  %s → %s\n", input, output))
+func clusterColors(args map[string]interface{}) (CallToolResult, error) {
+	colors, err := stringArrayArg(args, "colors")
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	method, _ := args["method"].(string)
+	threshold := internal.DefaultClusterThreshold
+	if t, ok := args["threshold"].(float64); ok {
+		threshold = t
+	}
+
+	count := 0
+	if c, ok := args["count"].(float64); ok {
+		if c != float64(int(c)) {
+			return CallToolResult{}, fmt.Errorf("count must be an integer")
 		}
+		count = int(c)
 	}
 
-	if len(errors) > 0 {
-		builder.WriteString("\nErrors:\n")
-		for input, errMsg := range errors {
-			builder.WriteString(fmt.Sprintf("  %s: %s\n", input, errMsg))
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	result, err := internal.ClusterColors(colors, internal.ClusterMethod(method), threshold, count, targetFormat, internal.ConvertOptions{PreserveAlpha: true})
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	return CallToolResult{
+		Content: []ContentItem{
+			{Type: "text", Text: internal.FormatClusters(result)},
+		},
+	}, nil
+}
+
+func extractPalette(args map[string]interface{}) (CallToolResult, error) {
+	path, _ := args["path"].(string)
+	data, _ := args["data"].(string)
+
+	img, format, err := internal.LoadImage(path, data)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	count := 0
+	if c, ok := args["count"].(float64); ok {
+		if c != float64(int(c)) {
+			return CallToolResult{}, fmt.Errorf("count must be an integer")
 		}
+		count = int(c)
+	}
+
+	method, _ := args["method"].(string)
+
+	targetFormat := "hex"
+	if tf, ok := args["target_format"].(string); ok {
+		targetFormat = tf
+	}
+
+	result, err := internal.ExtractPalette(img, format, count, internal.ExtractMethod(method), targetFormat, internal.ConvertOptions{PreserveAlpha: true})
+	if err != nil {
+		return CallToolResult{}, err
 	}
 
 	return CallToolResult{
 		Content: []ContentItem{
-			{Type: "text", Text: builder.String()},
+			{Type: "text", Text: internal.FormatExtractedPalette(result)},
 		},
 	}, nil
 }
 
+// stringArrayArg extracts a required, non-empty array of strings
+func stringArrayArg(args map[string]interface{}, name string) ([]string, error) {
+	items, ok := args[name].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s parameter is required and must be an array", name)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s array cannot be empty", name)
+	}
+
+	values := make([]string, 0, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s item at index %d is not a string", name, i)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// numberArrayArg extracts a required, non-empty array of numbers
+func numberArrayArg(args map[string]interface{}, name string) ([]float64, error) {
+	items, ok := args[name].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s parameter must be an array of numbers", name)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s array cannot be empty", name)
+	}
+
+	values := make([]float64, 0, len(items))
+	for i, item := range items {
+		v, ok := item.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s item at index %d is not a number", name, i)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
 func sendResponse(resp MCPResponse) {
 	data, err := json.Marshal(resp)
 	if err != nil {
@@ -204,3 +204,19 @@ func TestServerInfo(t *testing.T) {
 		t.Errorf("Version should follow semantic versioning: %s", serverVersion)
 	}
 }
+
+// TestCheckGamutDefaultMapping tests that an empty gamut_mapping selects css4 like an absent one
+func TestCheckGamutDefaultMapping(t *testing.T) {
+	for _, args := range []map[string]interface{}{
+		{"color": "color(display-p3 0 1 0)"},
+		{"color": "color(display-p3 0 1 0)", "gamut_mapping": ""},
+	} {
+		result, err := checkGamut(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := result.Content[0].Text; !strings.Contains(text, "Gamut mapping: css4") {
+			t.Errorf("expected css4 mapping for %v, got:\n%s", args, text)
+		}
+	}
+}